@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -10,20 +14,130 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"pokerbot/internal/bot"
+	"pokerbot/internal/engine"
+	"pokerbot/internal/history"
 	"pokerbot/internal/poker"
 )
 
-const helpText = `Привет! Я бот-покерный калькулятор для Техасского Холдэма.
+const helpText = `Привет! Я бот-покерный калькулятор для Техасского Холдэма и Омахи.
 Используйте /menu, чтобы открыть интерактивный конструктор запроса.
 
 Либо отправьте параметры текстом в формате:
 hand: Ah Kh
 players: 4
 style: tight
+styles: tight, loose, loose (необязательно, стиль для каждого соперника)
 board: Qh Jh Td
-trials: 7000 (необязательно)
+dead: 7c 2d (необязательно, карты вне игры: сброшенные и показанные, сожжённые)
+range: QQ+, AKs (необязательно)
+trials: 100000 (необязательно)
+pot: 120 (необязательно, банк вместе со ставкой соперника)
+call: 40 (необязательно, сумма колла — бот посчитает EV колла и фолда)
+stack: 900 (необязательно, эффективный стек для подразумеваемых шансов)
+fresh: yes (необязательно, пересчитать префлоп заново вместо готовой таблицы)
+precision: 0.5 или time: 5s (необязательно, вместо trials: остановиться при точности ±0.5% или через 5 секунд)
 
-Доступные стили: tight, balanced, loose.`
+Доступные стили: any (любые две карты, по умолчанию), tight (топ 15% рук), loose (топ 50%), balanced (топ 25%) или свой процент, например style: 22%.
+Для Омахи добавьте строку game: plo (или plo5) и укажите 4 (5) карты в hand.
+Для шорт-дека укажите game: shortdeck — колода из 36 карт (от шестёрок), флеш старше фулл-хауса.
+Диапазон задаётся стандартной нотацией (QQ+, AKs, ATo+, 76s-54s, KhQh, 22-88) и заменяет стиль.
+
+Диапазон против диапазона:
+/rvr QQ+,AK vs 22+,A2s+ board: Kh 7d 2c
+
+Ауты на флопе или тёрне:
+/outs Ah Kh board: 9h 5h 2c
+
+Текстура борда: спаренность, масти, связанность, натс:
+/board Qh Jh Td
+
+Кто что выиграл на вскрытии, с побочными банками:
+/showdown p1: Ah Kd p2: Qs Qc board: 2c 7d 9h Ts 3s stacks: 100 250
+
+Разбор раздачи PokerStars или GGPoker по улицам: пришлите файл истории рук (в подписи можно указать range: или style: для соперников) или вставьте текст после /replay
+
+Сыграть с ботом один на один (в личных сообщениях):
+/play stack: 1000 blinds: 5/10 aggression: normal
+
+Стол в групповом чате: /join, чтобы сесть, /deal — раздать, /leave — встать из-за стола
+
+Эквити турнира по ICM (без параметров — конструктор):
+/icm stacks: 1500 2300 800 payouts: 50 30 20`
+
+const rangeHelpText = `Формат команды:
+/rvr <диапазон героя> vs <диапазон соперника> [vs ...] [board: карты] [trials: N]
+
+Пример: /rvr QQ+,AK vs 22+,A2s+ board: Kh 7d 2c`
+
+const outsHelpText = `Формат команды:
+/outs <карты героя> board: <флоп или тёрн> [range: диапазон соперника] [game: shortdeck]
+
+Пример: /outs Ah Kh board: 9h 5h 2c range: 99+, AQ+`
+
+const boardHelpText = `Формат команды:
+/board <флоп, тёрн или ривер> [game: plo]
+
+Пример: /board Qh Jh Td`
+
+const showdownHelpText = `Формат команды:
+/showdown p1: <карты> p2: <карты> [p3: fold ...] board: <5 карт> stacks: <фишки, вложенные каждым игроком> [game: plo]
+
+Игроки перечисляются по порядку мест, начиная слева от баттона: в этом порядке раздаются лишние фишки при делёжке. Сбросивший игрок указывается как fold — его фишки остаются в банке.
+
+Пример: /showdown p1: Ah Kd p2: Qs Qc p3: Jh Jd board: 2c 7d 9h Ts 3s stacks: 100 250 400`
+
+const icmHelpText = `Формат команды:
+/icm stacks: <стеки игроков> payouts: <выплаты с первого места> [hero: N villain: N pot: банк equity: эквити]
+
+Для решения пуш/колл укажите номера героя и соперника в олл-ине, блайнды и анте в банке и эквити героя — процентом или картами, которые бот просчитает сам (hand: Ah 9h range: 22+, A2s+).
+
+Пример: /icm stacks: 1500 2300 800 payouts: 50 30 20 hero: 3 villain: 2 pot: 150 hand: Ah 9h`
+
+const replayHelpText = `Пришлите файл истории рук PokerStars или GGPoker документом либо вставьте текст раздачи после команды /replay.
+
+В подписи к файлу можно задать модель соперников, чьи карты не открылись: range: 22+, A2s+ или style: tight. Соперники, показавшие карты на вскрытии, считаются с ними.`
+
+const playHelpText = `Формат команды:
+/play stack: 1000 blinds: 5/10 aggression: normal
+
+Все параметры необязательны: по умолчанию стек 1000, блайнды 5/10 и обычная агрессия. Агрессия бота — passive, normal, aggressive или процент от 0 до 100.`
+
+// playDecisionTime bounds the simulations behind the bot's moves in a hand.
+const playDecisionTime = 10 * time.Second
+
+// botMove is a decision of the heads-up bot, made off the update loop.
+type botMove struct {
+	chatID int64
+	turn   bot.BotTurn
+	action engine.Action
+}
+
+const tableHelpText = `Стол для группового чата:
+/join — сесть за стол; первый игрок может задать stack: 1000 blinds: 5/10
+/deal — раздать карты, когда за столом двое или больше
+/leave — встать из-за стола
+
+Карты приходят каждому в личные сообщения, поэтому сначала откройте диалог с ботом и нажмите Start.`
+
+// tableTurnTime is how long a member at a group table has to act before the
+// bot checks or folds for them.
+const tableTurnTime = 60 * time.Second
+
+// tableTimeout identifies the turn a timer was started for.
+type tableTimeout struct {
+	chatID int64
+	number int
+	turn   int
+}
+
+// maxHistorySize bounds the hand history files the bot downloads.
+const maxHistorySize = 1 << 20
+
+// maxSimulationTime bounds a single simulation regardless of its settings.
+const maxSimulationTime = 2 * time.Minute
+
+// progressInterval throttles edits of the progress message.
+const progressInterval = time.Second
 
 func main() {
 	token := strings.TrimSpace(os.Getenv("TELEGRAM_BOT_TOKEN"))
@@ -43,45 +157,93 @@ func main() {
 
 	updates := api.GetUpdatesChan(updateConfig)
 	sessions := make(map[int64]*bot.Session)
+	jobs := bot.NewJobs()
+	// Turn timers of group tables report back here, so table state is only
+	// touched from this loop.
+	timeouts := make(chan tableTimeout)
+	// Likewise the heads-up bot decides in the background and its moves are
+	// applied here.
+	moves := make(chan botMove)
 
-	for update := range updates {
-		if update.CallbackQuery != nil {
-			handleCallback(api, update.CallbackQuery, sessions)
-			continue
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			handleUpdate(api, update, sessions, jobs, timeouts, moves)
+		case t := <-timeouts:
+			handleTableTimeout(api, t, sessions, timeouts)
+		case m := <-moves:
+			handleBotMove(api, m, sessions, moves)
 		}
+	}
+}
 
-		if update.Message == nil {
-			continue
-		}
+func handleUpdate(api *tgbotapi.BotAPI, update tgbotapi.Update, sessions map[int64]*bot.Session, jobs *bot.Jobs, timeouts chan<- tableTimeout, moves chan<- botMove) {
+	if update.CallbackQuery != nil {
+		handleCallback(api, update.CallbackQuery, sessions, jobs, timeouts, moves)
+		return
+	}
 
-		if update.Message.IsCommand() {
-			handleCommand(api, update.Message, sessions)
-			continue
+	if update.Message == nil {
+		return
+	}
+
+	if update.Message.Document != nil {
+		if isHandHistory(update.Message) {
+			handleDocument(api, update.Message, jobs)
 		}
+		return
+	}
 
-		handleTextMessage(api, update.Message, sessions)
+	if update.Message.IsCommand() {
+		handleCommand(api, update.Message, sessions, jobs, timeouts, moves)
+		return
 	}
+
+	handleTextMessage(api, update.Message, sessions, jobs)
 }
 
 func formatError(err error) string {
 	return fmt.Sprintf("Ошибка: %v\n\n%s", err, helpText)
 }
 
-func sendMessage(api *tgbotapi.BotAPI, msg tgbotapi.MessageConfig) {
+func sendMessage(api *tgbotapi.BotAPI, msg tgbotapi.Chattable) {
 	if _, err := api.Send(msg); err != nil {
 		log.Printf("ошибка отправки сообщения: %v", err)
 	}
 }
 
-func handleCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session) {
+func handleCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, jobs *bot.Jobs, timeouts chan<- tableTimeout, moves chan<- botMove) {
 	switch msg.Command() {
 	case "start":
 		sendHelp(api, msg)
 		startSession(api, msg.Chat.ID, sessions)
 	case "menu":
 		startSession(api, msg.Chat.ID, sessions)
+	case "rvr":
+		handleRangeCommand(api, msg, jobs)
+	case "outs":
+		handleOutsCommand(api, msg)
+	case "board":
+		handleBoardCommand(api, msg)
+	case "showdown":
+		handleShowdownCommand(api, msg)
+	case "replay":
+		handleReplayCommand(api, msg, jobs)
+	case "play":
+		handlePlayCommand(api, msg, sessions, moves)
+	case "join":
+		handleJoinCommand(api, msg, sessions)
+	case "leave":
+		handleLeaveCommand(api, msg, sessions, timeouts)
+	case "deal":
+		handleDealCommand(api, msg, sessions, timeouts)
+	case "icm":
+		handleICMCommand(api, msg, sessions, jobs)
 	case "cancel":
-		delete(sessions, msg.Chat.ID)
+		resetSession(sessions, msg.Chat.ID)
 		reply := tgbotapi.NewMessage(msg.Chat.ID, "Конструктор сброшен.")
 		reply.ReplyToMessageID = msg.MessageID
 		sendMessage(api, reply)
@@ -90,6 +252,562 @@ func handleCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int
 	}
 }
 
+func handleRangeCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs) {
+	req, err := bot.ParseRangeRequest(msg.CommandArguments())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v\n\n%s", err, rangeHelpText))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	runSimulation(api, msg, jobs, req.ToSimulationConfig(), func(result poker.SimulationResult) string {
+		return bot.FormatRangeResult(req, result)
+	})
+}
+
+func handleOutsCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	req, err := bot.ParseOutsRequest(msg.CommandArguments())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v\n\n%s", err, outsHelpText))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	result, err := poker.CalculateOuts(req.ToOutsConfig())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка расчёта: %v", err))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, bot.FormatOutsResult(req, result))
+	reply.ReplyToMessageID = msg.MessageID
+	sendMessage(api, reply)
+}
+
+func handleBoardCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	req, err := bot.ParseBoardRequest(msg.CommandArguments())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v\n\n%s", err, boardHelpText))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	tex, err := poker.AnalyzeBoard(req.Game, req.Board)
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка расчёта: %v", err))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, bot.FormatBoardTexture(tex))
+	reply.ReplyToMessageID = msg.MessageID
+	sendMessage(api, reply)
+}
+
+func handleShowdownCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message) {
+	req, err := bot.ParseShowdownRequest(msg.CommandArguments())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v\n\n%s", err, showdownHelpText))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	result, err := poker.ResolveShowdown(req.ToShowdownConfig())
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка расчёта: %v", err))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, bot.FormatShowdownResult(req, result))
+	reply.ReplyToMessageID = msg.MessageID
+	sendMessage(api, reply)
+}
+
+func handleReplayCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs) {
+	text := msg.CommandArguments()
+	if strings.TrimSpace(text) == "" {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, replayHelpText)
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+	replayHistory(api, msg, jobs, bot.ReplayRequest{Style: poker.StyleAny}, func(context.Context) (string, error) {
+		return text, nil
+	})
+}
+
+// isHandHistory reports whether a document should be replayed. Group chats
+// share all kinds of files, so there only a .txt export or a caption with
+// replay options marks a hand history; private chats replay every document.
+func isHandHistory(msg *tgbotapi.Message) bool {
+	if msg.Chat.IsPrivate() || strings.HasSuffix(strings.ToLower(msg.Document.FileName), ".txt") {
+		return true
+	}
+	if strings.TrimSpace(msg.Caption) == "" {
+		return false
+	}
+	_, err := bot.ParseReplayOptions(msg.Caption)
+	return err == nil
+}
+
+// handleDocument replays an uploaded hand history file.
+func handleDocument(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs) {
+	reply := func(text string) {
+		r := tgbotapi.NewMessage(msg.Chat.ID, text)
+		r.ReplyToMessageID = msg.MessageID
+		sendMessage(api, r)
+	}
+	if msg.Document.FileSize > maxHistorySize {
+		reply("Файл слишком большой: история рук должна занимать не больше 1 МБ.")
+		return
+	}
+	req, err := bot.ParseReplayOptions(msg.Caption)
+	if err != nil {
+		reply(fmt.Sprintf("Ошибка: %v\n\n%s", err, replayHelpText))
+		return
+	}
+
+	replayHistory(api, msg, jobs, req, func(ctx context.Context) (string, error) {
+		url, err := api.GetFileDirectURL(msg.Document.FileID)
+		if err != nil {
+			return "", err
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxHistorySize))
+		return string(data), err
+	})
+}
+
+// replayHistory loads the hand history, then replies with an annotated
+// report for each of the first bot.MaxReplayHands hands. Load errors may
+// carry the file URL with the bot token, so they are only logged.
+func replayHistory(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs, req bot.ReplayRequest, load func(context.Context) (string, error)) {
+	chatID := msg.Chat.ID
+	ctx, done, ok := jobs.Start(context.Background(), chatID)
+	if !ok {
+		reply := tgbotapi.NewMessage(chatID, "Дождитесь окончания текущего расчёта или остановите его.")
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	status := tgbotapi.NewMessage(chatID, "Разбираю раздачи…")
+	status.ReplyToMessageID = msg.MessageID
+	status.ReplyMarkup = bot.StopSimulationKeyboard()
+	sent, err := api.Send(status)
+	if err != nil {
+		log.Printf("ошибка отправки сообщения: %v", err)
+		done()
+		return
+	}
+
+	go func() {
+		defer done()
+		ctx, cancel := context.WithTimeout(ctx, maxSimulationTime)
+		defer cancel()
+
+		finish := func(text string) {
+			sendMessage(api, tgbotapi.NewEditMessageText(chatID, sent.MessageID, text))
+		}
+		text, err := load(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			finish("Разбор остановлен.")
+			return
+		case err != nil:
+			log.Printf("ошибка загрузки истории рук: %v", err)
+			finish("Не удалось загрузить файл.")
+			return
+		}
+		hands, err := history.Parse(text)
+		if err != nil {
+			finish(fmt.Sprintf("Ошибка: %v\n\n%s", err, replayHelpText))
+			return
+		}
+
+		for _, h := range hands[:min(len(hands), bot.MaxReplayHands)] {
+			cfg := req.ToReplayConfig()
+			cfg.Seed = time.Now().UnixNano()
+			reports, err := history.Replay(ctx, h, cfg)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				finish("Разбор остановлен.")
+				return
+			case err != nil:
+				sendMessage(api, tgbotapi.NewMessage(chatID, fmt.Sprintf("Раздача #%s: ошибка симуляции: %v", h.ID, err)))
+			default:
+				sendMessage(api, tgbotapi.NewMessage(chatID, bot.FormatReplay(req, h, reports)))
+			}
+		}
+
+		summary := fmt.Sprintf("Готово: разобрано раздач — %d.", min(len(hands), bot.MaxReplayHands))
+		if len(hands) > bot.MaxReplayHands {
+			summary += fmt.Sprintf(" Остальные %d пропущены: за раз разбирается не больше %d.", len(hands)-bot.MaxReplayHands, bot.MaxReplayHands)
+		}
+		finish(summary)
+	}()
+}
+
+func handlePlayCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, moves chan<- botMove) {
+	reply := func(text string) {
+		r := tgbotapi.NewMessage(msg.Chat.ID, text)
+		r.ReplyToMessageID = msg.MessageID
+		sendMessage(api, r)
+	}
+	if !msg.Chat.IsPrivate() {
+		reply("Играть с ботом один на один можно в личных сообщениях.")
+		return
+	}
+	req, err := bot.ParsePlayRequest(msg.CommandArguments())
+	if err != nil {
+		reply(fmt.Sprintf("Ошибка: %v\n\n%s", err, playHelpText))
+		return
+	}
+
+	sess := sessions[msg.Chat.ID]
+	if sess == nil {
+		s := bot.NewSession()
+		sess = &s
+		sessions[msg.Chat.ID] = sess
+	}
+	sess.Play = bot.NewHeadsUp(req, time.Now().UnixNano())
+	dealPlayHand(api, msg.Chat.ID, sess.Play, moves)
+}
+
+// dealPlayHand deals the next hand of the match in a new message.
+func dealPlayHand(api *tgbotapi.BotAPI, chatID int64, game *bot.HeadsUp, moves chan<- botMove) {
+	if err := game.Deal(); err != nil {
+		log.Printf("ошибка раздачи: %v", err)
+		sendMessage(api, tgbotapi.NewMessage(chatID, "Ошибка расчёта"))
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, bot.FormatHeadsUp(game))
+	if keyboard := bot.PlayKeyboard(game); len(keyboard.InlineKeyboard) > 0 {
+		msg.ReplyMarkup = keyboard
+	}
+	sent, err := api.Send(msg)
+	if err != nil {
+		log.Printf("ошибка отправки сообщения: %v", err)
+	}
+	game.MessageID = sent.MessageID
+	startBotTurn(chatID, game, moves)
+}
+
+// handlePlayAction applies the user's button press and updates the table
+// message in place.
+func handlePlayAction(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, game *bot.HeadsUp, moves chan<- botMove) {
+	chatID := cb.Message.Chat.ID
+	number, turn, action, ok := bot.ParseActionCallback(bot.CallbackPlayAction, cb.Data)
+	if !ok || game == nil || number != game.Number {
+		sendMessage(api, tgbotapi.NewMessage(chatID, "Эта раздача уже закончилась. Новая игра: /play"))
+		return
+	}
+
+	if err := game.Act(turn, action); err != nil {
+		if errors.Is(err, bot.ErrNotYourTurn) {
+			// A repeated or stale press; the message already shows the hand.
+			return
+		}
+		sendMessage(api, tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %v", err)))
+		return
+	}
+	updatePlayMessage(api, chatID, game)
+	startBotTurn(chatID, game, moves)
+}
+
+// startBotTurn lets the bot decide in the background when it is to act, so
+// its simulations do not hold up other chats.
+func startBotTurn(chatID int64, game *bot.HeadsUp, moves chan<- botMove) {
+	turn, ok := game.BotTurn()
+	if !ok {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), playDecisionTime)
+		defer cancel()
+		moves <- botMove{chatID: chatID, turn: turn, action: turn.Decide(ctx)}
+	}()
+}
+
+// handleBotMove applies a decision of the bot unless the match moved on
+// while it was thinking.
+func handleBotMove(api *tgbotapi.BotAPI, m botMove, sessions map[int64]*bot.Session, moves chan<- botMove) {
+	sess := sessions[m.chatID]
+	if sess == nil || sess.Play == nil {
+		return
+	}
+	game := sess.Play
+	if err := game.ApplyBot(m.turn, m.action); err != nil {
+		if !errors.Is(err, bot.ErrNotYourTurn) {
+			log.Printf("ошибка хода бота: %v", err)
+		}
+		return
+	}
+	updatePlayMessage(api, m.chatID, game)
+	startBotTurn(m.chatID, game, moves)
+}
+
+func updatePlayMessage(api *tgbotapi.BotAPI, chatID int64, game *bot.HeadsUp) {
+	sendMessage(api, tgbotapi.NewEditMessageTextAndMarkup(chatID, game.MessageID, bot.FormatHeadsUp(game), bot.PlayKeyboard(game)))
+}
+
+// resetSession clears the menu builders and the heads-up match but keeps a
+// group table, which belongs to every member at it.
+func resetSession(sessions map[int64]*bot.Session, chatID int64) {
+	sess := sessions[chatID]
+	if sess == nil || sess.Table == nil {
+		delete(sessions, chatID)
+		return
+	}
+	fresh := bot.NewSession()
+	fresh.Table = sess.Table
+	sessions[chatID] = &fresh
+}
+
+// groupTable returns the table of a group chat, replying when the chat is
+// not a group or has no table yet and create is false.
+func groupTable(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, create bool) (*bot.Session, bool) {
+	reply := func(text string) {
+		r := tgbotapi.NewMessage(msg.Chat.ID, text)
+		r.ReplyToMessageID = msg.MessageID
+		sendMessage(api, r)
+	}
+	if !msg.Chat.IsGroup() && !msg.Chat.IsSuperGroup() {
+		reply("Стол собирается в групповом чате. Сыграть с ботом один на один: /play")
+		return nil, false
+	}
+	sess := sessions[msg.Chat.ID]
+	if sess == nil && create {
+		s := bot.NewSession()
+		sess = &s
+		sessions[msg.Chat.ID] = sess
+	}
+	if sess == nil || sess.Table == nil && !create {
+		reply("За столом пока никого нет. Сесть за стол: /join\n\n" + tableHelpText)
+		return nil, false
+	}
+	return sess, true
+}
+
+func handleJoinCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session) {
+	reply := func(text string) {
+		r := tgbotapi.NewMessage(msg.Chat.ID, text)
+		r.ReplyToMessageID = msg.MessageID
+		sendMessage(api, r)
+	}
+	sess, ok := groupTable(api, msg, sessions, true)
+	if !ok {
+		return
+	}
+	table := sess.Table
+	if table == nil {
+		req, err := bot.ParsePlayRequest(msg.CommandArguments())
+		if err != nil {
+			reply(fmt.Sprintf("Ошибка: %v\n\n%s", err, tableHelpText))
+			return
+		}
+		table = bot.NewTable(req, time.Now().UnixNano())
+	}
+	if err := table.CanJoin(msg.From.ID); err != nil {
+		reply(fmt.Sprintf("Ошибка: %v", err))
+		return
+	}
+
+	// Hole cards go out in private messages, which need the member to have
+	// started a chat with the bot. The table opens with its first member.
+	welcome := tgbotapi.NewMessage(msg.From.ID, fmt.Sprintf("Вы за столом в «%s». Сюда будут приходить ваши карты.", msg.Chat.Title))
+	if _, err := api.Send(welcome); err != nil {
+		reply("Не могу написать вам в личные сообщения: откройте диалог с ботом, нажмите Start и снова отправьте /join.")
+		return
+	}
+	if err := table.Join(msg.From.ID, playerName(msg.From)); err != nil {
+		reply(fmt.Sprintf("Ошибка: %v", err))
+		return
+	}
+	sess.Table = table
+	text := bot.FormatTableLobby(table)
+	if table.InHand() {
+		text += "\n\nИдёт раздача — вы сыграете со следующей."
+	}
+	reply(text)
+}
+
+func handleLeaveCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, timeouts chan<- tableTimeout) {
+	sess, ok := groupTable(api, msg, sessions, false)
+	if !ok {
+		return
+	}
+	table := sess.Table
+	turn := table.Turn
+	if err := table.Leave(msg.From.ID); err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v", err))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+	sendMessage(api, tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("%s встаёт из-за стола.", playerName(msg.From))))
+	if table.Hand != nil && table.Turn != turn {
+		// The member folded on their turn; show the hand moving on.
+		updateTableMessage(api, msg.Chat.ID, table, timeouts)
+	}
+}
+
+func handleDealCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, timeouts chan<- tableTimeout) {
+	sess, ok := groupTable(api, msg, sessions, false)
+	if !ok {
+		return
+	}
+	dealTableHand(api, msg.Chat.ID, msg.Chat.Title, sess.Table, timeouts)
+}
+
+// dealTableHand starts a hand at a group table: hole cards go to every player
+// privately and the table is posted to the group.
+func dealTableHand(api *tgbotapi.BotAPI, chatID int64, title string, table *bot.Table, timeouts chan<- tableTimeout) {
+	if err := table.Deal(); err != nil {
+		sendMessage(api, tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: %v\n\n%s", err, tableHelpText)))
+		return
+	}
+	for seat := range table.Hand.Seats() {
+		sendMessage(api, tgbotapi.NewMessage(table.Players[seat].UserID, bot.FormatHoleCards(table, seat, title)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, bot.FormatTable(table, tableTurnTime))
+	msg.ReplyMarkup = bot.TableKeyboard(table)
+	sent, err := api.Send(msg)
+	if err != nil {
+		log.Printf("ошибка отправки сообщения: %v", err)
+		return
+	}
+	table.MessageID = sent.MessageID
+	scheduleTableTimeout(chatID, table, timeouts)
+}
+
+// updateTableMessage redraws the hand after an action and restarts the turn
+// timer.
+func updateTableMessage(api *tgbotapi.BotAPI, chatID int64, table *bot.Table, timeouts chan<- tableTimeout) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, table.MessageID, bot.FormatTable(table, tableTurnTime), bot.TableKeyboard(table))
+	sendMessage(api, edit)
+	scheduleTableTimeout(chatID, table, timeouts)
+}
+
+func scheduleTableTimeout(chatID int64, table *bot.Table, timeouts chan<- tableTimeout) {
+	if !table.InHand() {
+		return
+	}
+	t := tableTimeout{chatID: chatID, number: table.Number, turn: table.Turn}
+	time.AfterFunc(tableTurnTime, func() { timeouts <- t })
+}
+
+func handleTableTimeout(api *tgbotapi.BotAPI, t tableTimeout, sessions map[int64]*bot.Session, timeouts chan<- tableTimeout) {
+	sess := sessions[t.chatID]
+	if sess == nil || sess.Table == nil {
+		return
+	}
+	player := sess.Table.ToAct()
+	if player == nil {
+		return
+	}
+	name := player.Name
+	action, ok := sess.Table.Timeout(t.number, t.turn)
+	if !ok {
+		return
+	}
+	move := "фолд"
+	if action.Kind == engine.ActionCheck {
+		move = "чек"
+	}
+	sendMessage(api, tgbotapi.NewMessage(t.chatID, fmt.Sprintf("Время на ход у игрока %s истекло — %s.", name, move)))
+	updateTableMessage(api, t.chatID, sess.Table, timeouts)
+}
+
+// handleTableAction applies a member's button press and returns the notice
+// shown to them when the press is rejected.
+func handleTableAction(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, table *bot.Table, timeouts chan<- tableTimeout) string {
+	number, turn, action, ok := bot.ParseActionCallback(bot.CallbackTableAction, cb.Data)
+	if !ok || table == nil || number != table.Number {
+		return "Эта раздача уже закончилась."
+	}
+	if err := table.Act(cb.From.ID, turn, action); err != nil {
+		if errors.Is(err, bot.ErrNotYourTurn) {
+			return "Сейчас не ваш ход."
+		}
+		return fmt.Sprintf("Ошибка: %v", err)
+	}
+	updateTableMessage(api, cb.Message.Chat.ID, table, timeouts)
+	return ""
+}
+
+func playerName(u *tgbotapi.User) string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+func handleICMCommand(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, jobs *bot.Jobs) {
+	args := strings.TrimSpace(msg.CommandArguments())
+	if args == "" {
+		sendMenu(api, msg.Chat.ID, openMenu(sessions, msg.Chat.ID, bot.NewICMSession()))
+		return
+	}
+
+	req, err := bot.ParseICMRequest(args)
+	if err != nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка: %v\n\n%s", err, icmHelpText))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+	respondWithICM(api, msg, jobs, req)
+}
+
+// respondWithICM replies with the ICM breakdown, simulating the hero's all-in
+// equity first when the request gives a hand instead of a percentage.
+func respondWithICM(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs, req bot.ICMRequest) {
+	format := func(equity float64) string {
+		text, err := bot.FormatICMResult(req, equity)
+		if err != nil {
+			return fmt.Sprintf("Ошибка расчёта: %v", err)
+		}
+		return text
+	}
+
+	if !req.NeedsEquity() {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, format(req.Equity))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+
+	eq := req.EquityRequest()
+	if result, ok := eq.PreflopResult(); ok {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, format(result.Equity))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+	runSimulation(api, msg, jobs, eq.ToSimulationConfig(), func(result poker.SimulationResult) string {
+		return format(result.Equity)
+	})
+}
+
 func sendHelp(api *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 	reply := tgbotapi.NewMessage(msg.Chat.ID, helpText)
 	reply.ReplyToMessageID = msg.MessageID
@@ -97,19 +815,34 @@ func sendHelp(api *tgbotapi.BotAPI, msg *tgbotapi.Message) {
 }
 
 func startSession(api *tgbotapi.BotAPI, chatID int64, sessions map[int64]*bot.Session) {
-	sess := bot.NewSession()
-	sessions[chatID] = &sess
-	sendMenu(api, chatID, sessions[chatID])
+	sendMenu(api, chatID, openMenu(sessions, chatID, bot.NewSession()))
+}
+
+// openMenu replaces the menu builders of the chat with fresh, keeping a
+// heads-up match or a group table in progress.
+func openMenu(sessions map[int64]*bot.Session, chatID int64, fresh bot.Session) *bot.Session {
+	if sess := sessions[chatID]; sess != nil {
+		fresh.Play = sess.Play
+		fresh.Table = sess.Table
+	}
+	sessions[chatID] = &fresh
+	return &fresh
 }
 
 func sendMenu(api *tgbotapi.BotAPI, chatID int64, sess *bot.Session) {
+	if sess.Menu == bot.MenuICM {
+		msg := tgbotapi.NewMessage(chatID, bot.ICMSummary(*sess))
+		msg.ReplyMarkup = bot.ICMKeyboard()
+		sendMessage(api, msg)
+		return
+	}
 	msg := tgbotapi.NewMessage(chatID, bot.SessionSummary(*sess))
 	markup := bot.MenuKeyboard()
 	msg.ReplyMarkup = markup
 	sendMessage(api, msg)
 }
 
-func handleTextMessage(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session) {
+func handleTextMessage(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map[int64]*bot.Session, jobs *bot.Jobs) {
 	chatID := msg.Chat.ID
 	text := strings.TrimSpace(msg.Text)
 	if text == "" {
@@ -130,7 +863,7 @@ func handleTextMessage(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sessions map
 		return
 	}
 
-	respondWithSimulation(api, msg, req)
+	respondWithSimulation(api, msg, jobs, req)
 }
 
 func handleAwaitingInput(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sess *bot.Session) {
@@ -147,25 +880,72 @@ func handleAwaitingInput(api *tgbotapi.BotAPI, msg *tgbotapi.Message, sess *bot.
 	sendMessage(api, ack)
 }
 
-func respondWithSimulation(api *tgbotapi.BotAPI, msg *tgbotapi.Message, req bot.Request) {
-	cfg := req.ToSimulationConfig()
-	cfg.Seed = time.Now().UnixNano()
+func respondWithSimulation(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs, req bot.Request) {
+	if result, ok := req.PreflopResult(); ok {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, bot.FormatResult(req, result))
+		reply.ReplyToMessageID = msg.MessageID
+		sendMessage(api, reply)
+		return
+	}
+	runSimulation(api, msg, jobs, req.ToSimulationConfig(), func(result poker.SimulationResult) string {
+		return bot.FormatResult(req, result)
+	})
+}
 
-	result, err := poker.SimulateWinProbability(cfg)
-	if err != nil {
-		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("Ошибка симуляции: %v", err))
+// runSimulation posts a progress message with a stop button and runs the
+// simulation in the background, so the update loop stays free to handle the
+// button. The progress message is edited into the final result.
+func runSimulation(api *tgbotapi.BotAPI, msg *tgbotapi.Message, jobs *bot.Jobs, cfg poker.SimulationConfig, format func(poker.SimulationResult) string) {
+	chatID := msg.Chat.ID
+	ctx, done, ok := jobs.Start(context.Background(), chatID)
+	if !ok {
+		reply := tgbotapi.NewMessage(chatID, "Дождитесь окончания текущего расчёта или остановите его.")
 		reply.ReplyToMessageID = msg.MessageID
 		sendMessage(api, reply)
 		return
 	}
 
-	response := bot.FormatResult(req, result)
-	respMessage := tgbotapi.NewMessage(msg.Chat.ID, response)
-	respMessage.ReplyToMessageID = msg.MessageID
-	sendMessage(api, respMessage)
+	status := tgbotapi.NewMessage(chatID, "Считаю…")
+	status.ReplyToMessageID = msg.MessageID
+	status.ReplyMarkup = bot.StopSimulationKeyboard()
+	sent, err := api.Send(status)
+	if err != nil {
+		log.Printf("ошибка отправки сообщения: %v", err)
+		done()
+		return
+	}
+
+	cfg.Seed = time.Now().UnixNano()
+	lastUpdate := time.Now()
+	cfg.Progress = func(p poker.Progress) {
+		if time.Since(lastUpdate) < progressInterval || p.Fraction >= 1 {
+			return
+		}
+		lastUpdate = time.Now()
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, sent.MessageID, bot.FormatProgress(p), bot.StopSimulationKeyboard())
+		sendMessage(api, edit)
+	}
+
+	go func() {
+		defer done()
+		ctx, cancel := context.WithTimeout(ctx, maxSimulationTime)
+		defer cancel()
+
+		var text string
+		result, err := poker.SimulateWinProbabilityContext(ctx, cfg)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			text = "Расчёт остановлен."
+		case err != nil:
+			text = fmt.Sprintf("Ошибка симуляции: %v", err)
+		default:
+			text = format(result)
+		}
+		sendMessage(api, tgbotapi.NewEditMessageText(chatID, sent.MessageID, text))
+	}()
 }
 
-func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions map[int64]*bot.Session) {
+func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions map[int64]*bot.Session, jobs *bot.Jobs, timeouts chan<- tableTimeout, moves chan<- botMove) {
 	chatID := cb.Message.Chat.ID
 	sess := sessions[chatID]
 	if sess == nil {
@@ -175,6 +955,8 @@ func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions m
 	}
 
 	data := cb.Data
+	// notice is shown to the user who pressed the button.
+	notice := ""
 
 	switch {
 	case data == bot.CallbackSetHand:
@@ -186,6 +968,18 @@ func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions m
 	case data == bot.CallbackSetBoard:
 		sess.Await = bot.StepBoard
 		promptForStep(api, chatID, bot.StepBoard)
+	case data == bot.CallbackSetDead:
+		sess.Await = bot.StepDead
+		promptForStep(api, chatID, bot.StepDead)
+	case data == bot.CallbackSetSeats:
+		sess.Await = bot.StepStyles
+		promptForStep(api, chatID, bot.StepStyles)
+	case data == bot.CallbackSetRange:
+		sess.Await = bot.StepRange
+		promptForStep(api, chatID, bot.StepRange)
+	case data == bot.CallbackSetPot:
+		sess.Await = bot.StepPot
+		promptForStep(api, chatID, bot.StepPot)
 	case data == bot.CallbackSetTrials:
 		sess.Await = bot.StepTrials
 		promptForStep(api, chatID, bot.StepTrials)
@@ -198,6 +992,18 @@ func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions m
 		}
 	case data == bot.CallbackSetStyle:
 		promptStyleSelection(api, chatID)
+	case strings.HasPrefix(data, bot.CallbackStylePercent):
+		if percent, ok := bot.ParseStylePercentCallback(data); ok {
+			edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, cb.Message.MessageID, bot.StylePercentText(percent), bot.StylePercentKeyboard(percent))
+			sendMessage(api, edit)
+		}
+	case strings.HasPrefix(data, bot.CallbackSetGame):
+		if game, ok := bot.ParseGameCallback(data); ok {
+			sess.SetGame(game)
+			sendMenu(api, chatID, sess)
+		} else {
+			promptGameSelection(api, chatID)
+		}
 	case data == bot.CallbackSimulate:
 		if !sess.HasRequiredFields() {
 			reply := tgbotapi.NewMessage(chatID, "Сначала заполните карты и количество игроков.")
@@ -207,9 +1013,61 @@ func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions m
 
 		req := sess.Request
 		req.Trials = sess.Request.Trials
-		respondWithSimulation(api, cb.Message, req)
+		respondWithSimulation(api, cb.Message, jobs, req)
+	case data == bot.CallbackSetStacks:
+		sess.Await = bot.StepStacks
+		promptForStep(api, chatID, bot.StepStacks)
+	case data == bot.CallbackSetPayouts:
+		sess.Await = bot.StepPayouts
+		promptForStep(api, chatID, bot.StepPayouts)
+	case data == bot.CallbackSetAllIn:
+		sess.Await = bot.StepAllIn
+		promptForStep(api, chatID, bot.StepAllIn)
+	case data == bot.CallbackSetEquity:
+		sess.Await = bot.StepEquity
+		promptForStep(api, chatID, bot.StepEquity)
+	case data == bot.CallbackICMCalculate:
+		if err := sess.ICMReady(); err != nil {
+			sendMessage(api, tgbotapi.NewMessage(chatID, fmt.Sprintf("Сначала заполните параметры: %v", err)))
+			break
+		}
+		respondWithICM(api, cb.Message, jobs, sess.ICM)
+	case strings.HasPrefix(data, bot.CallbackPlayAction):
+		handlePlayAction(api, cb, sess.Play, moves)
+	case data == bot.CallbackPlayNext:
+		if sess.Play == nil || sess.Play.Over() {
+			sendMessage(api, tgbotapi.NewMessage(chatID, "Игра не начата. Новая игра: /play"))
+			break
+		}
+		sendMessage(api, tgbotapi.NewEditMessageReplyMarkup(chatID, cb.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+		dealPlayHand(api, chatID, sess.Play, moves)
+	case data == bot.CallbackPlayQuit:
+		if sess.Play == nil {
+			sendMessage(api, tgbotapi.NewMessage(chatID, "Игра не начата. Новая игра: /play"))
+			break
+		}
+		sendMessage(api, tgbotapi.NewEditMessageReplyMarkup(chatID, cb.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+		sendMessage(api, tgbotapi.NewMessage(chatID, bot.FormatPlayQuit(sess.Play)))
+		sess.Play = nil
+	case strings.HasPrefix(data, bot.CallbackTableAction):
+		notice = handleTableAction(api, cb, sess.Table, timeouts)
+	case data == bot.CallbackTableNext:
+		if sess.Table == nil || sess.Table.InHand() {
+			notice = "Раздача уже идёт."
+			break
+		}
+		if !sess.Table.Seated(cb.From.ID) {
+			notice = "Сдавать могут только игроки за столом."
+			break
+		}
+		sendMessage(api, tgbotapi.NewEditMessageReplyMarkup(chatID, cb.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+		dealTableHand(api, chatID, cb.Message.Chat.Title, sess.Table, timeouts)
+	case data == bot.CallbackStopSimulation:
+		if !jobs.Cancel(chatID) {
+			sendMessage(api, tgbotapi.NewMessage(chatID, "Нет активного расчёта."))
+		}
 	case data == bot.CallbackCancel:
-		delete(sessions, chatID)
+		resetSession(sessions, chatID)
 		reply := tgbotapi.NewMessage(chatID, "Конструктор очищен. Используйте /menu для нового запроса.")
 		sendMessage(api, reply)
 	default:
@@ -217,7 +1075,7 @@ func handleCallback(api *tgbotapi.BotAPI, cb *tgbotapi.CallbackQuery, sessions m
 		sendMessage(api, reply)
 	}
 
-	if _, err := api.Request(tgbotapi.NewCallback(cb.ID, "")); err != nil {
+	if _, err := api.Request(tgbotapi.NewCallback(cb.ID, notice)); err != nil {
 		log.Printf("ошибка ответа на callback: %v", err)
 	}
 }
@@ -232,7 +1090,7 @@ func promptForStep(api *tgbotapi.BotAPI, chatID int64, step bot.InputStep) {
 	var text, placeholder string
 	switch step {
 	case bot.StepHand:
-		text = "Введите две карты героя (например: Ah Kh)"
+		text = "Введите карты героя: две для холдема и шорт-дека, четыре для PLO4, пять для PLO5 (например: Ah Kh)"
 		placeholder = "Ah Kh"
 	case bot.StepPlayers:
 		text = "Сколько игроков за столом?"
@@ -240,9 +1098,33 @@ func promptForStep(api *tgbotapi.BotAPI, chatID int64, step bot.InputStep) {
 	case bot.StepBoard:
 		text = "Введите известные карты борда (можно оставить пустым)"
 		placeholder = "Qh Jh Th"
+	case bot.StepDead:
+		text = "Введите карты, вышедшие из игры (показанный сброс, сожжённые карты), или \"-\", чтобы убрать"
+		placeholder = "7c 2d"
+	case bot.StepStyles:
+		text = "Перечислите стили соперников по местам через запятую (tight, balanced, loose) или \"-\", чтобы всем задать общий стиль"
+		placeholder = "tight, loose, loose"
+	case bot.StepRange:
+		text = "Введите диапазон соперников (например: QQ+, AKs, ATo+) или \"-\", чтобы играть по стилю"
+		placeholder = "QQ+, AKs"
 	case bot.StepTrials:
 		text = "Сколько симуляций выполнить?"
-		placeholder = "7000"
+		placeholder = "100000"
+	case bot.StepPot:
+		text = "Введите банк (вместе со ставкой соперника), сумму колла и, по желанию, эффективный стек через пробел или \"-\", чтобы убрать"
+		placeholder = "120 40 900"
+	case bot.StepStacks:
+		text = "Введите стеки игроков через пробел"
+		placeholder = "1500 2300 800"
+	case bot.StepPayouts:
+		text = "Введите выплаты призовых мест, начиная с первого"
+		placeholder = "50 30 20"
+	case bot.StepAllIn:
+		text = "Введите номер героя, номер соперника в олл-ине и, по желанию, блайнды и анте в банке или \"-\", чтобы убрать решение"
+		placeholder = "3 2 150"
+	case bot.StepEquity:
+		text = "Введите эквити героя в процентах или его карты, по желанию с диапазоном соперника после vs"
+		placeholder = "Ah 9h vs 22+, A2s+"
 	default:
 		text = "Введите значение"
 		placeholder = ""
@@ -256,3 +1138,10 @@ func promptStyleSelection(api *tgbotapi.BotAPI, chatID int64) {
 	msg.ReplyMarkup = markup
 	sendMessage(api, msg)
 }
+
+func promptGameSelection(api *tgbotapi.BotAPI, chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, "Выберите вариант игры:")
+	markup := bot.GameKeyboard()
+	msg.ReplyMarkup = markup
+	sendMessage(api, msg)
+}
@@ -0,0 +1,35 @@
+// Command preflopgen regenerates the preflop equity table embedded in the
+// poker package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"pokerbot/internal/poker"
+)
+
+func main() {
+	out := flag.String("out", "internal/poker/preflop.txt", "path of the generated table")
+	trials := flag.Int("trials", 100000, "Monte Carlo trials per hand, table size and style")
+	seed := flag.Int64("seed", 1, "base seed, so the table can be reproduced")
+	flag.Parse()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("не удалось создать файл: %v", err)
+	}
+	defer f.Close()
+
+	err = poker.GeneratePreflopTable(f, *trials, *seed, func(done, total int) {
+		if done%100 == 0 || done == total {
+			fmt.Fprintf(os.Stderr, "\r%d/%d", done, total)
+		}
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		log.Fatalf("не удалось построить таблицу: %v", err)
+	}
+}
@@ -2,4 +2,4 @@ module pokerbot
 
 go 1.24.1
 
-require github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1 // indirect
+require github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"pokerbot/internal/poker"
+)
+
+// BoardRequest captures a board texture query sent with the /board command.
+type BoardRequest struct {
+	Game  poker.Game
+	Board []poker.Card
+}
+
+var boardKeyPattern = regexp.MustCompile(`(?i)(game|игра)\s*:`)
+
+// ParseBoardRequest parses text like "Qh Jh Td game: plo".
+func ParseBoardRequest(text string) (BoardRequest, error) {
+	var req BoardRequest
+
+	boardText := text
+	if loc := boardKeyPattern.FindStringIndex(text); loc != nil {
+		boardText = text[:loc[0]]
+		value := strings.TrimSpace(text[loc[1]:])
+		game, ok := gameAliases[normalize(value)]
+		if !ok {
+			return BoardRequest{}, fmt.Errorf("unknown game: %s", value)
+		}
+		req.Game = game
+	}
+
+	board, err := parseCards(boardText)
+	if err != nil {
+		return BoardRequest{}, fmt.Errorf("board: %w", err)
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return BoardRequest{}, fmt.Errorf("board: expected a flop, turn or river, got %d cards", len(board))
+	}
+	if err := checkDistinct(board); err != nil {
+		return BoardRequest{}, fmt.Errorf("board: %w", err)
+	}
+	req.Board = board
+	return req, nil
+}
+
+// FormatBoardTexture describes the board and the hands it allows.
+func FormatBoardTexture(tex poker.BoardTexture) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Борд: %s (%s)\n", CardsToText(tex.Board), streetDisplay(len(tex.Board)))
+	if tex.Game != poker.GameHoldem {
+		fmt.Fprintf(&b, "Игра: %s\n", gameDisplay(tex.Game))
+	}
+	fmt.Fprintf(&b, "Спаренность: %s\n", pairingDisplay(tex.Pairing))
+	fmt.Fprintf(&b, "Масти: %s\n", suitTextureDisplay(tex.Suits))
+	fmt.Fprintf(&b, "Связанность: %s\n", connectednessDisplay(tex.Connectedness))
+
+	if len(tex.Straights) > 0 {
+		highs := make([]string, len(tex.Straights))
+		for i, r := range tex.Straights {
+			highs[i] = r.String()
+		}
+		fmt.Fprintf(&b, "Возможные стриты (старшая карта): %s\n", strings.Join(highs, ", "))
+	}
+	var draws []string
+	if tex.FlushDraw {
+		draws = append(draws, "флеш-дро")
+	}
+	if tex.StraightDraw {
+		draws = append(draws, "стрит-дро")
+	}
+	if len(draws) > 0 {
+		fmt.Fprintf(&b, "Возможные дро: %s\n", strings.Join(draws, ", "))
+	}
+
+	fmt.Fprintf(&b, "\nНатс: %s — %s\n", strings.ToLower(categoryDisplay(tex.Nuts.Category)), combosDisplay(tex.NutCombos, maxNutCombos))
+	fmt.Fprintf(&b, "Натс на руках: %d из %d комбинаций (%.2f%%)\n", len(tex.NutCombos), tex.Holdings, tex.NutShare())
+	if tex.Game.IsOmaha() {
+		b.WriteString("В Омахе считаются пары карт, которые рука разыгрывает.\n")
+	}
+
+	b.WriteString("\nГотовые руки (доля всех комбинаций):\n")
+	for category := poker.StraightFlush; category >= poker.HighCard; category-- {
+		if tex.Made[category] > 0 {
+			fmt.Fprintf(&b, "%s — %.2f%%\n", categoryDisplay(category), tex.Share(category))
+		}
+	}
+	return b.String()
+}
+
+// maxNutCombos caps how many nut holdings are spelled out card by card;
+// more are grouped into hand classes such as AKs.
+const maxNutCombos = 6
+
+func combosDisplay(combos []poker.Combo, limit int) string {
+	var parts []string
+	if len(combos) <= limit {
+		for _, c := range combos {
+			if c[1].Rank > c[0].Rank {
+				c[0], c[1] = c[1], c[0]
+			}
+			parts = append(parts, c[0].String()+" "+c[1].String())
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range combos {
+		if class := c.Class(); !seen[class] {
+			seen[class] = true
+			parts = append(parts, class)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func streetDisplay(cards int) string {
+	switch cards {
+	case 3:
+		return "флоп"
+	case 4:
+		return "тёрн"
+	default:
+		return "ривер"
+	}
+}
+
+func pairingDisplay(category poker.HandCategory) string {
+	switch category {
+	case poker.OnePair:
+		return "спаренный"
+	case poker.TwoPair:
+		return "две пары на борде"
+	case poker.ThreeOfAKind:
+		return "трипс на борде"
+	case poker.FullHouse:
+		return "фулл-хаус на борде"
+	case poker.FourOfAKind:
+		return "каре на борде"
+	default:
+		return "не спаренный"
+	}
+}
+
+func suitTextureDisplay(suits poker.SuitTexture) string {
+	switch suits {
+	case poker.TwoTone:
+		return "две карты одной масти"
+	case poker.FlushBoard:
+		return "три и больше карт одной масти, флеш возможен"
+	case poker.Monotone:
+		return "одномастный, флеш возможен"
+	default:
+		return "радуга (все масти разные)"
+	}
+}
+
+func connectednessDisplay(c poker.Connectedness) string {
+	switch c {
+	case poker.Gapped:
+		return "полусвязанный (две карты в пределах стрита)"
+	case poker.Connected:
+		return "связанный, стрит возможен"
+	default:
+		return "несвязанный"
+	}
+}
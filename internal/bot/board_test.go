@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+func TestParseBoardRequest(t *testing.T) {
+	req, err := ParseBoardRequest("Qh Jh Td игра: plo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Game != poker.GameOmaha4 || len(req.Board) != 3 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	for _, text := range []string{
+		"",
+		"Qh Jh",
+		"Qh Jh Td 9c 8c 7c",
+		"Qh Qh Td",
+		"Qh Jh Td game: bridge",
+	} {
+		if _, err := ParseBoardRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestFormatBoardTexture(t *testing.T) {
+	req, err := ParseBoardRequest("Qh Jh Td")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tex, err := poker.AnalyzeBoard(req.Game, req.Board)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := FormatBoardTexture(tex)
+	for _, fragment := range []string{
+		"Борд: Qh Jh Td (флоп)",
+		"Масти: две карты одной масти",
+		"Связанность: связанный, стрит возможен",
+		"Возможные стриты (старшая карта): A, K, Q",
+		"Возможные дро: флеш-дро, стрит-дро",
+		"Натс: стрит — AKs, AKo",
+		"Натс на руках: 16 из 1176 комбинаций (1.36%)",
+		"Стрит — 4.08%",
+	} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package bot
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"pokerbot/internal/poker"
@@ -11,23 +13,142 @@ import (
 func FormatResult(req Request, result poker.SimulationResult) string {
 	var b strings.Builder
 	b.WriteString("Вероятности:\n")
-	fmt.Fprintf(&b, "Победа: %.2f%%\n", result.Win)
-	fmt.Fprintf(&b, "Ничья: %.2f%%\n", result.Tie)
-	fmt.Fprintf(&b, "Поражение: %.2f%%\n\n", result.Lose)
+	fmt.Fprintf(&b, "Победа: %s\n", percentWithMargin(result.Win, result.WinMargin))
+	fmt.Fprintf(&b, "Ничья: %s\n", percentWithMargin(result.Tie, result.TieMargin))
+	fmt.Fprintf(&b, "Поражение: %s\n", percentWithMargin(result.Lose, result.LoseMargin))
+	fmt.Fprintf(&b, "Эквити (доля банка): %s\n\n", percentWithMargin(result.Equity, result.EquityMargin))
 
+	fmt.Fprintf(&b, "Игра: %s\n", gameDisplay(req.Game))
 	fmt.Fprintf(&b, "Игроков за столом: %d (оппонентов: %d)\n", req.Players, req.Players-1)
-	fmt.Fprintf(&b, "Стиль соперников: %s\n", styleDisplay(req.Style))
-	fmt.Fprintf(&b, "Симуляций: %d\n", req.Trials)
+	switch {
+	case !req.Range.IsEmpty():
+		fmt.Fprintf(&b, "Диапазон соперников: %s (%d комбо)\n", req.Range, req.Range.Len())
+	case len(req.Styles) > 0:
+		fmt.Fprintf(&b, "Стили соперников: %s\n", stylesDisplay(req.Styles))
+	default:
+		fmt.Fprintf(&b, "Стиль соперников: %s\n", styleDisplay(req.Style))
+	}
+	switch result.Method {
+	case poker.MethodExact:
+		fmt.Fprintf(&b, "Расчёт: точный перебор (%d исходов)\n", result.Samples)
+	case poker.MethodPrecomputed:
+		fmt.Fprintf(&b, "Расчёт: готовая таблица префлопа (%d симуляций на руку, для нового расчёта добавьте fresh: yes)\n", result.Samples)
+	default:
+		fmt.Fprintf(&b, "Симуляций: %d\n", simulatedTrials(req, result))
+	}
+	if result.Partial {
+		b.WriteString("Расчёт остановлен досрочно, результат предварительный\n")
+	}
 	fmt.Fprintf(&b, "Ваши карты: %s\n", CardsToText(req.Hand))
 	if len(req.Board) > 0 {
 		fmt.Fprintf(&b, "Карты на столе: %s\n", CardsToText(req.Board))
+		if rank, five, err := poker.EvaluateHandCards(req.Game, req.Hand, req.Board); err == nil {
+			fmt.Fprintf(&b, "Ваша рука сейчас: %s (%s)\n", HandDisplay(rank), CardsToText(five))
+		}
 	} else {
 		b.WriteString("Карты на столе: пока нет\n")
 	}
+	if len(req.Dead) > 0 {
+		fmt.Fprintf(&b, "Мёртвые карты: %s\n", CardsToText(req.Dead))
+	}
+
+	if len(req.Styles) > 0 && len(result.Seats) == len(req.Styles) {
+		b.WriteString("\nПо соперникам:\n")
+		for i, seat := range result.Seats {
+			fmt.Fprintf(&b, "%d. %s — обыгрывает вас в %.2f%%\n", i+1, styleDisplay(req.Styles[i]), seat.BeatsHero)
+		}
+	}
+
+	writeCategories(&b, result)
+	writeDecision(&b, req, result)
 
 	return b.String()
 }
 
+// writeDecision appends the call/fold advice when the request has a bet to call.
+func writeDecision(b *strings.Builder, req Request, result poker.SimulationResult) {
+	if req.Call == 0 {
+		return
+	}
+	equity := result.Equity
+	advice, err := poker.Advise(equity, poker.Decision{
+		Pot:         req.Pot,
+		Call:        req.Call,
+		Stack:       req.Stack,
+		CardsToCome: 5 - len(req.Board),
+	})
+	if err != nil {
+		return
+	}
+
+	b.WriteString("\nРешение:\n")
+	fmt.Fprintf(b, "Банк: %s, колл: %s", formatAmount(req.Pot), formatAmount(advice.Call))
+	if req.Stack > 0 {
+		fmt.Fprintf(b, ", стек: %s", formatAmount(req.Stack))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(b, "Нужное эквити: %.2f%% (ваше: %.2f%%)\n", advice.RequiredEquity, equity)
+	fmt.Fprintf(b, "EV колла: %+.2f, EV фолда: 0\n", advice.CallEV)
+	switch advice.Recommendation {
+	case poker.RecommendCall:
+		b.WriteString("Рекомендация: колл\n")
+	case poker.RecommendCallImplied:
+		fmt.Fprintf(b, "Рекомендация: колл с расчётом на подразумеваемые шансы — нужно выиграть ещё не меньше %s на следующих улицах\n", formatAmount(advice.ImpliedNeeded))
+	default:
+		b.WriteString("Рекомендация: фолд\n")
+		if advice.ImpliedNeeded > 0 && len(req.Board) < 5 {
+			fmt.Fprintf(b, "Колл окупится, только если выиграть ещё %s на следующих улицах\n", formatAmount(advice.ImpliedNeeded))
+		}
+	}
+}
+
+// formatAmount prints chips without trailing zeros, rounded to cents.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// writeCategories renders the hero's final hands next to the hands that beat
+// the hero, skipping categories that never occurred.
+func writeCategories(b *strings.Builder, result poker.SimulationResult) {
+	if result.HeroHands == ([len(result.HeroHands)]float64{}) {
+		return
+	}
+	b.WriteString("\nКомбинации (у вас / у победителя при поражении):\n")
+	for category := poker.HighCard; category <= poker.StraightFlush; category++ {
+		hero, lost := result.HeroHands[category], result.LostTo[category]
+		if hero == 0 && lost == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "%s: %.2f%% / %.2f%%\n", categoryDisplay(category), hero, lost)
+	}
+}
+
+// FormatProgress describes a running simulation for the progress message.
+func FormatProgress(p poker.Progress) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Считаю… %d%%\n", int(p.Fraction*100))
+	fmt.Fprintf(&b, "Симуляций: %d\n", p.Done)
+	fmt.Fprintf(&b, "Победа сейчас: %s", percentWithMargin(p.Result.Win, p.Result.WinMargin))
+	return b.String()
+}
+
+// percentWithMargin renders a percentage with its 95% confidence margin, when known.
+func percentWithMargin(value, margin float64) string {
+	if margin == 0 {
+		return fmt.Sprintf("%.2f%%", value)
+	}
+	return fmt.Sprintf("%.2f%% ± %.2f%%", value, margin)
+}
+
+// simulatedTrials prefers the number of trials actually played, which differs
+// from the request when the simulator stops adaptively.
+func simulatedTrials(req Request, result poker.SimulationResult) int {
+	if result.Samples > 0 {
+		return result.Samples
+	}
+	return req.Trials
+}
+
 func CardsToText(cards []poker.Card) string {
 	parts := make([]string, len(cards))
 	for i, c := range cards {
@@ -36,13 +157,115 @@ func CardsToText(cards []poker.Card) string {
 	return strings.Join(parts, " ")
 }
 
+func gameDisplay(game poker.Game) string {
+	switch game {
+	case poker.GameOmaha4:
+		return "Омаха PLO4"
+	case poker.GameOmaha5:
+		return "Омаха PLO5"
+	case poker.GameShortDeck:
+		return "Шорт-дек (6+)"
+	default:
+		return "Техасский холдем"
+	}
+}
+
+func categoryDisplay(category poker.HandCategory) string {
+	switch category {
+	case poker.OnePair:
+		return "Пара"
+	case poker.TwoPair:
+		return "Две пары"
+	case poker.ThreeOfAKind:
+		return "Сет/трипс"
+	case poker.Straight:
+		return "Стрит"
+	case poker.Flush:
+		return "Флеш"
+	case poker.FullHouse:
+		return "Фулл-хаус"
+	case poker.FourOfAKind:
+		return "Каре"
+	case poker.StraightFlush:
+		return "Стрит-флеш"
+	default:
+		return "Старшая карта"
+	}
+}
+
+// rankForms holds the Russian forms of each rank name: nominative singular,
+// genitive singular, nominative plural and genitive plural.
+var rankForms = [...][4]string{
+	{"двойка", "двойки", "двойки", "двоек"},
+	{"тройка", "тройки", "тройки", "троек"},
+	{"четвёрка", "четвёрки", "четвёрки", "четвёрок"},
+	{"пятёрка", "пятёрки", "пятёрки", "пятёрок"},
+	{"шестёрка", "шестёрки", "шестёрки", "шестёрок"},
+	{"семёрка", "семёрки", "семёрки", "семёрок"},
+	{"восьмёрка", "восьмёрки", "восьмёрки", "восьмёрок"},
+	{"девятка", "девятки", "девятки", "девяток"},
+	{"десятка", "десятки", "десятки", "десяток"},
+	{"валет", "валета", "валеты", "валетов"},
+	{"дама", "дамы", "дамы", "дам"},
+	{"король", "короля", "короли", "королей"},
+	{"туз", "туза", "тузы", "тузов"},
+}
+
+// HandDisplay describes the hand in Russian, such as "Флеш от короля" or
+// "Две пары: тузы и семёрки, кикер дама".
+func HandDisplay(h poker.HandRank) string {
+	name := func(r poker.Rank) string { return rankForms[r][0] }
+	of := func(r poker.Rank) string { return rankForms[r][1] }
+	many := func(r poker.Rank) string { return rankForms[r][2] }
+	manyOf := func(r poker.Rank) string { return rankForms[r][3] }
+
+	v := h.Values
+	switch h.Category {
+	case poker.OnePair:
+		return fmt.Sprintf("Пара %s, кикер %s", manyOf(v[0]), name(v[1]))
+	case poker.TwoPair:
+		return fmt.Sprintf("Две пары: %s и %s, кикер %s", many(v[0]), many(v[1]), name(v[2]))
+	case poker.ThreeOfAKind:
+		return fmt.Sprintf("Три %s, кикер %s", of(v[0]), name(v[1]))
+	case poker.Straight:
+		return fmt.Sprintf("Стрит до %s", of(v[0]))
+	case poker.Flush:
+		return fmt.Sprintf("Флеш от %s", of(v[0]))
+	case poker.FullHouse:
+		return fmt.Sprintf("Фулл-хаус: %s и %s", many(v[0]), many(v[1]))
+	case poker.FourOfAKind:
+		return fmt.Sprintf("Каре %s, кикер %s", manyOf(v[0]), name(v[1]))
+	case poker.StraightFlush:
+		if v[0] == poker.Ace {
+			return "Роял-флеш"
+		}
+		return fmt.Sprintf("Стрит-флеш до %s", of(v[0]))
+	default:
+		return fmt.Sprintf("Старшая карта %s, кикер %s", name(v[0]), name(v[1]))
+	}
+}
+
+func stylesDisplay(styles []poker.PlayerStyle) string {
+	parts := make([]string, len(styles))
+	for i, s := range styles {
+		parts[i] = styleDisplay(s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// styleDisplay names the style together with the share of hands it plays.
 func styleDisplay(style poker.PlayerStyle) string {
-	switch style {
-	case poker.StyleTight:
-		return "тайтовый"
-	case poker.StyleLoose:
-		return "лузовый"
+	top := "топ " + strconv.FormatFloat(style.Percent(), 'f', -1, 64) + "%"
+	switch {
+	case style.IsCustom():
+		return top
+	case style == poker.StyleTight:
+		return "тайтовый (" + top + ")"
+	case style == poker.StyleLoose:
+		return "лузовый (" + top + ")"
+	case style == poker.StyleBalanced:
+		return "сбалансированный (" + top + ")"
 	default:
-		return "сбалансированный"
+		return "любые две карты"
 	}
 }
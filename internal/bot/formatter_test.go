@@ -16,12 +16,181 @@ func TestFormatResult(t *testing.T) {
 		Trials:  5000,
 	}
 
-	res := poker.SimulationResult{Win: 55.5, Tie: 3.3, Lose: 41.2}
+	res := poker.SimulationResult{Win: 55.5, Tie: 3.3, Lose: 41.2, Equity: 56.6}
 	text := FormatResult(req, res)
 
-	for _, fragment := range []string{"55.50", "Игроков за столом: 4", "тайтовый", "Ah Kh", "Карты на столе"} {
+	for _, fragment := range []string{"55.50", "Эквити (доля банка): 56.60%", "Игра: Техасский холдем", "Игроков за столом: 4", "тайтовый", "Ah Kh", "Карты на столе", "Ваша рука сейчас: Роял-флеш (Ah Kh Qh Jh Th)"} {
 		if !strings.Contains(text, fragment) {
 			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
 		}
 	}
 }
+
+func TestHandDisplay(t *testing.T) {
+	tests := []struct {
+		cards string
+		want  string
+	}{
+		{"Ah Kd 9c 7s 3h", "Старшая карта туз, кикер король"},
+		{"Qh Qd Ac 7d 3h", "Пара дам, кикер туз"},
+		{"Ah Ad 7c 7s Qd", "Две пары: тузы и семёрки, кикер дама"},
+		{"Th Td Ts Kd 2c", "Три десятки, кикер король"},
+		{"Ah 2d 3c 4s 5d", "Стрит до пятёрки"},
+		{"Kh 9h 7h 5h 2h", "Флеш от короля"},
+		{"Kh Kd Ks 7d 7c", "Фулл-хаус: короли и семёрки"},
+		{"Jh Jd Jc Js 9d", "Каре валетов, кикер девятка"},
+		{"Ah Kh Qh Jh Th", "Роял-флеш"},
+	}
+	for _, tc := range tests {
+		cards, err := parseCards(tc.cards)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rank, err := poker.EvaluateBestHand(cards)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := HandDisplay(rank); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.cards, tc.want, got)
+		}
+	}
+}
+
+func TestFormatResultRange(t *testing.T) {
+	req := Request{
+		Hand:    []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Players: 2,
+		Trials:  7000,
+		Range:   poker.MustParseRange("QQ+, AKs"),
+	}
+
+	text := FormatResult(req, poker.SimulationResult{Win: 40, Tie: 5, Lose: 55})
+	if !strings.Contains(text, "Диапазон соперников: QQ+, AKs (22 комбо)") {
+		t.Fatalf("expected range to be reported, got: %s", text)
+	}
+}
+
+func TestFormatResultSeats(t *testing.T) {
+	req := Request{
+		Hand:    []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Players: 3,
+		Trials:  7000,
+		Styles:  []poker.PlayerStyle{poker.StyleTight, poker.StyleLoose},
+	}
+
+	res := poker.SimulationResult{
+		Win: 50, Tie: 2, Lose: 48,
+		Seats: []poker.SeatResult{{BeatsHero: 30.5}, {BeatsHero: 21.25}},
+	}
+	text := FormatResult(req, res)
+
+	for _, fragment := range []string{"Стили соперников: тайтовый (топ 15%), лузовый (топ 50%)", "1. тайтовый (топ 15%) — обыгрывает вас в 30.50%", "2. лузовый (топ 50%) — обыгрывает вас в 21.25%"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+}
+
+func TestFormatResultExact(t *testing.T) {
+	req := Request{
+		Hand:    []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Players: 2,
+		Trials:  7000,
+	}
+
+	res := poker.SimulationResult{Win: 70, Tie: 5, Lose: 25, Method: poker.MethodExact, Samples: 990}
+	text := FormatResult(req, res)
+
+	if !strings.Contains(text, "точный перебор (990 исходов)") {
+		t.Fatalf("expected exact method to be reported, got: %s", text)
+	}
+	if strings.Contains(text, "Симуляций") {
+		t.Fatalf("exact result must not mention trials, got: %s", text)
+	}
+}
+
+func TestFormatResultCategories(t *testing.T) {
+	req := Request{
+		Hand:    []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Players: 2,
+		Trials:  5000,
+	}
+	res := poker.SimulationResult{Win: 60, Lose: 40}
+	res.HeroHands[poker.OnePair] = 70
+	res.HeroHands[poker.Flush] = 30
+	res.LostTo[poker.Flush] = 18
+
+	text := FormatResult(req, res)
+	for _, fragment := range []string{"Пара: 70.00% / 0.00%", "Флеш: 30.00% / 18.00%"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+	if strings.Contains(text, "Каре") {
+		t.Fatalf("expected empty categories to be skipped, got: %s", text)
+	}
+
+	if text := FormatResult(req, poker.SimulationResult{Win: 60, Lose: 40}); strings.Contains(text, "Комбинации") {
+		t.Fatalf("expected no table without category data, got: %s", text)
+	}
+}
+
+func TestFormatResultMargins(t *testing.T) {
+	req := Request{
+		Hand:      []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Players:   2,
+		Precision: 0.5,
+	}
+	res := poker.SimulationResult{Win: 63.2, Tie: 1.8, Lose: 35, WinMargin: 0.4, TieMargin: 0.1, LoseMargin: 0.4, Samples: 60000}
+
+	text := FormatResult(req, res)
+	for _, fragment := range []string{"Победа: 63.20% ± 0.40%", "Ничья: 1.80% ± 0.10%", "Симуляций: 60000"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+}
+
+func TestFormatProgressAndPartial(t *testing.T) {
+	p := poker.Progress{Done: 40000, Fraction: 0.4, Result: poker.SimulationResult{Win: 63.1, WinMargin: 0.5}}
+	text := FormatProgress(p)
+	for _, fragment := range []string{"Считаю… 40%", "Симуляций: 40000", "63.10% ± 0.50%"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected progress to contain %q, got: %s", fragment, text)
+		}
+	}
+
+	req := Request{Hand: []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")}, Players: 2, Trials: 100000}
+	text = FormatResult(req, poker.SimulationResult{Win: 63, Lose: 37, Samples: 40000, Partial: true})
+	if !strings.Contains(text, "Симуляций: 40000") || !strings.Contains(text, "остановлен досрочно") {
+		t.Fatalf("expected a partial result note, got: %s", text)
+	}
+}
+
+func TestFormatResultDecision(t *testing.T) {
+	req := Request{
+		Hand:    []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")},
+		Board:   []poker.Card{poker.MustParseCard("Qh"), poker.MustParseCard("7h"), poker.MustParseCard("2c"), poker.MustParseCard("3d")},
+		Players: 2,
+		Pot:     200,
+		Call:    100,
+		Stack:   1000,
+	}
+
+	text := FormatResult(req, poker.SimulationResult{Win: 20, Lose: 80, Equity: 20})
+	for _, fragment := range []string{"Банк: 200, колл: 100, стек: 1000", "Нужное эквити: 33.33% (ваше: 20.00%)", "EV колла: -40.00", "подразумеваемые шансы", "не меньше 200"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+
+	text = FormatResult(req, poker.SimulationResult{Win: 60, Tie: 10, Lose: 30, Equity: 65})
+	if !strings.Contains(text, "EV колла: +95.00") || !strings.Contains(text, "Рекомендация: колл\n") {
+		t.Fatalf("expected a call, got: %s", text)
+	}
+
+	req.Call = 0
+	if text := FormatResult(req, poker.SimulationResult{Win: 60, Lose: 40}); strings.Contains(text, "Решение") {
+		t.Fatalf("expected no decision without a bet, got: %s", text)
+	}
+}
@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"pokerbot/internal/history"
+	"pokerbot/internal/poker"
+)
+
+// MaxReplayHands caps how many hands of an uploaded file are replayed.
+const MaxReplayHands = 5
+
+// ReplayRequest holds the options sent with a hand history, such as the
+// caption of an uploaded file.
+type ReplayRequest struct {
+	// Style and Range model opponents whose cards were never shown.
+	Style poker.PlayerStyle
+	Range poker.Range
+}
+
+var replayKeyPattern = regexp.MustCompile(`(?i)(style|стиль|range|диапазон|рейндж)\s*:`)
+
+// ParseReplayOptions parses text like "range: 22+, A2s+" or "style: tight";
+// empty text leaves opponents with any two cards.
+func ParseReplayOptions(text string) (ReplayRequest, error) {
+	req := ReplayRequest{Style: poker.StyleAny}
+
+	keys := replayKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(keys) == 0 && strings.TrimSpace(text) != "" {
+		return ReplayRequest{}, fmt.Errorf("expected style: or range: in the caption")
+	}
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		var err error
+		switch key {
+		case "style", "стиль":
+			req.Style, err = parseStyle(value)
+		default:
+			req.Range, err = poker.ParseRange(value)
+		}
+		if err != nil {
+			return ReplayRequest{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return req, nil
+}
+
+// ToReplayConfig converts the options into the replay configuration.
+func (r ReplayRequest) ToReplayConfig() history.ReplayConfig {
+	return history.ReplayConfig{Style: r.Style, Range: r.Range}
+}
+
+// FormatReplay annotates a hand street by street with the hero's equity at
+// the start of each street and the play that followed.
+func FormatReplay(req ReplayRequest, h history.Hand, reports []history.StreetReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Раздача #%s (%s, %s, %s)\n", h.ID, h.Site, gameDisplay(h.Game), h.Stakes)
+	fmt.Fprintf(&b, "Герой: %s, карты %s\n", h.Hero, CardsToText(h.Cards[h.Hero]))
+	switch {
+	case !req.Range.IsEmpty() && !h.Game.IsOmaha():
+		fmt.Fprintf(&b, "Неизвестные карты соперников: диапазон %s\n", req.Range)
+	default:
+		fmt.Fprintf(&b, "Неизвестные карты соперников: %s\n", styleDisplay(req.Style))
+	}
+
+	for _, r := range reports {
+		b.WriteString("\n")
+		b.WriteString(streetName(r.Street))
+		if len(r.Board) > 0 {
+			fmt.Fprintf(&b, ": %s, банк %s", CardsToText(r.Board), formatAmount(r.Pot))
+		}
+		b.WriteString("\n")
+
+		opponents := make([]string, len(r.Opponents))
+		for i, name := range r.Opponents {
+			opponents[i] = name
+			if cards, shown := h.Cards[name]; shown {
+				opponents[i] += " (" + CardsToText(cards) + ")"
+			}
+		}
+		fmt.Fprintf(&b, "Против: %s\n", strings.Join(opponents, ", "))
+		fmt.Fprintf(&b, "Эквити: %s\n", percentWithMargin(r.Result.Equity, r.Result.EquityMargin))
+		if len(r.Actions) > 0 {
+			actions := make([]string, len(r.Actions))
+			for i, a := range r.Actions {
+				actions[i] = actionDisplay(a)
+			}
+			fmt.Fprintf(&b, "Действия: %s\n", strings.Join(actions, ", "))
+		}
+	}
+
+	if won := h.Won[h.Hero]; won > 0 {
+		fmt.Fprintf(&b, "\nИтог: %s забирает %s\n", h.Hero, formatAmount(won))
+	} else {
+		b.WriteString("\nИтог: банк ушёл сопернику\n")
+	}
+	return b.String()
+}
+
+func streetName(s history.Street) string {
+	switch s {
+	case history.Flop:
+		return "Флоп"
+	case history.Turn:
+		return "Тёрн"
+	case history.River:
+		return "Ривер"
+	default:
+		return "Префлоп"
+	}
+}
+
+func actionDisplay(a history.Action) string {
+	var text string
+	switch a.Kind {
+	case history.ActionPost:
+		text = "ставит " + formatAmount(a.Amount)
+	case history.ActionFold:
+		text = "фолд"
+	case history.ActionCheck:
+		text = "чек"
+	case history.ActionCall:
+		text = "колл " + formatAmount(a.Amount)
+	case history.ActionBet:
+		text = "бет " + formatAmount(a.Amount)
+	case history.ActionRaise:
+		text = "рейз до " + formatAmount(a.To)
+	case history.ActionReturn:
+		text = "возврат " + formatAmount(a.Amount)
+	}
+	if a.AllIn {
+		text += " (олл-ин)"
+	}
+	return a.Player + " " + text
+}
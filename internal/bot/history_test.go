@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"pokerbot/internal/history"
+	"pokerbot/internal/poker"
+)
+
+const replayHand = `PokerStars Hand #1001:  Hold'em No Limit (10/20) - 2024/01/15 20:31:07 ET
+Table 'Home' 2-max Seat #1 is the button
+Seat 1: villain (2000 in chips)
+Seat 2: Hero (2000 in chips)
+villain: posts small blind 10
+Hero: posts big blind 20
+*** HOLE CARDS ***
+Dealt to Hero [Ah Kd]
+villain: raises 40 to 60
+Hero: calls 40
+*** FLOP *** [Qh Jh 2c]
+Hero: checks
+villain: bets 80 and is all-in
+Hero: folds
+Uncalled bet (80) returned to villain
+villain collected 120 from pot
+*** SUMMARY ***
+Total pot 120 | Rake 0
+Board [Qh Jh 2c]`
+
+func TestParseReplayOptions(t *testing.T) {
+	req, err := ParseReplayOptions("")
+	if err != nil || req.Style != poker.StyleAny || !req.Range.IsEmpty() {
+		t.Fatalf("unexpected defaults %+v, %v", req, err)
+	}
+	req, err = ParseReplayOptions("стиль: tight диапазон: 22+, A2s+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Style != poker.StyleTight || req.Range.String() != "22+, A2s+" {
+		t.Fatalf("unexpected options %+v", req)
+	}
+	for _, text := range []string{"hello", "style: wild", "range: ZZ"} {
+		if _, err := ParseReplayOptions(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestFormatReplay(t *testing.T) {
+	hands, err := history.Parse(replayHand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, err := ParseReplayOptions("range: QQ+, AK")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := req.ToReplayConfig()
+	cfg.Trials, cfg.Seed = 2000, 1
+	reports, err := history.Replay(context.Background(), hands[0], cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := FormatReplay(req, hands[0], reports)
+	for _, fragment := range []string{
+		"Раздача #1001 (PokerStars, Техасский холдем, 10/20)",
+		"Герой: Hero, карты Ah Kd",
+		"Неизвестные карты соперников: диапазон QQ+, AK",
+		"Действия: villain ставит 10, Hero ставит 20, villain рейз до 60, Hero колл 40",
+		"Флоп: Qh Jh 2c, банк 120",
+		"villain бет 80 (олл-ин), Hero фолд, villain возврат 80",
+		"Итог: банк ушёл сопернику",
+	} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"pokerbot/internal/poker"
+)
+
+// ICMRequest captures a tournament equity query sent with the /icm command or
+// built in the ICM menu.
+type ICMRequest struct {
+	Stacks  []float64
+	Payouts []float64
+	// Hero and Villain number the players of an all-in from 1; zero means no
+	// push/call decision.
+	Hero, Villain int
+	// Pot holds the blinds and antes already in the middle.
+	Pot float64
+	// Hand and Range let the simulator find the hero's all-in equity; without
+	// a hand Equity is used as given.
+	Hand      []poker.Card
+	Range     poker.Range
+	Equity    float64
+	hasEquity bool
+}
+
+var icmKeyPattern = regexp.MustCompile(`(?i)(stacks|стеки|payouts|выплаты|призы|hero|герой|villain|соперник|pot|банк|equity|эквити|hand|карты|range|диапазон)\s*:`)
+
+// ParseICMRequest parses text like
+// "stacks: 1500 2300 800 payouts: 50 30 20 hero: 3 villain: 2 hand: Ah 9h".
+func ParseICMRequest(text string) (ICMRequest, error) {
+	var req ICMRequest
+
+	keys := icmKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(keys) == 0 || strings.TrimSpace(text[:keys[0][0]]) != "" {
+		return ICMRequest{}, fmt.Errorf("expected key: value pairs such as stacks: and payouts:")
+	}
+
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		var err error
+		switch key {
+		case "stacks", "стеки":
+			req.Stacks, err = parseAmounts(value)
+		case "payouts", "выплаты", "призы":
+			req.Payouts, err = parseAmounts(value)
+		case "hero", "герой":
+			req.Hero, err = parseInt(value)
+		case "villain", "соперник":
+			req.Villain, err = parseInt(value)
+		case "pot", "банк":
+			req.Pot, err = parseAmount(value)
+		case "equity", "эквити":
+			err = req.setEquity(value)
+		case "hand", "карты":
+			req.Hand, err = parseCards(value)
+		case "range", "диапазон":
+			req.Range, err = poker.ParseRange(value)
+		}
+		if err != nil {
+			return ICMRequest{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if err := req.check(); err != nil {
+		return ICMRequest{}, err
+	}
+	return req, nil
+}
+
+// setEquity reads the hero's all-in equity: a percentage such as "45%", or
+// hole cards optionally followed by the opponent's range, as in "Ah 9h vs 22+".
+func (r *ICMRequest) setEquity(value string) error {
+	if equity, err := parsePercent(value); err == nil {
+		if equity > 100 {
+			return fmt.Errorf("value must be between 0 and 100")
+		}
+		r.Equity, r.hasEquity = equity, true
+		r.Hand, r.Range = nil, poker.Range{}
+		return nil
+	}
+
+	handText, rangeText, versus := strings.Cut(versusPattern.ReplaceAllString(value, " vs "), " vs ")
+	hand, err := parseCards(handText)
+	if err != nil {
+		return err
+	}
+	if len(hand) != 2 {
+		return fmt.Errorf("expected a percentage or 2 hole cards, got %d cards", len(hand))
+	}
+	var villain poker.Range
+	if versus {
+		if villain, err = poker.ParseRange(rangeText); err != nil {
+			return err
+		}
+	}
+	r.Hand, r.Range = hand, villain
+	r.Equity, r.hasEquity = 0, false
+	return nil
+}
+
+// check validates the request before it is calculated.
+func (r ICMRequest) check() error {
+	if len(r.Stacks) < 2 || len(r.Stacks) > poker.MaxICMPlayers {
+		return fmt.Errorf("stacks: expected 2 to %d players, got %d", poker.MaxICMPlayers, len(r.Stacks))
+	}
+	if len(r.Payouts) == 0 {
+		return fmt.Errorf("payouts: specify the prizes from first place down")
+	}
+	if r.Hero == 0 && r.Villain == 0 {
+		return nil
+	}
+
+	for _, seat := range []int{r.Hero, r.Villain} {
+		if seat < 1 || seat > len(r.Stacks) {
+			return fmt.Errorf("hero and villain: expected player numbers from 1 to %d", len(r.Stacks))
+		}
+	}
+	if r.Hero == r.Villain {
+		return fmt.Errorf("hero and villain: expected two different players")
+	}
+	if len(r.Hand) == 0 && !r.hasEquity {
+		return fmt.Errorf("equity: specify the hero's equity or hand")
+	}
+	if len(r.Hand) > 0 {
+		if len(r.Hand) != 2 {
+			return fmt.Errorf("hand: expected 2 cards, got %d", len(r.Hand))
+		}
+		if r.Hand[0] == r.Hand[1] {
+			return fmt.Errorf("hand: duplicate cards")
+		}
+	}
+	return nil
+}
+
+// HasDecision reports whether the request includes a push/call decision.
+func (r ICMRequest) HasDecision() bool {
+	return r.Hero > 0 && r.Villain > 0
+}
+
+// NeedsEquity reports whether the hero's equity must be simulated first.
+func (r ICMRequest) NeedsEquity() bool {
+	return r.HasDecision() && len(r.Hand) > 0
+}
+
+// EquityRequest describes the all-in as a heads-up Hold'em simulation against
+// the opponent's range, or any two cards without one.
+func (r ICMRequest) EquityRequest() Request {
+	return Request{
+		Hand:    r.Hand,
+		Players: 2,
+		Style:   poker.StyleAny,
+		Trials:  DefaultTrials,
+		Range:   r.Range,
+	}
+}
+
+// FormatICMResult lists every player's prize equity and, for an all-in, the
+// value of folding and calling. Equity is the hero's all-in equity in percent
+// and is ignored without a decision.
+func FormatICMResult(req ICMRequest, equity float64) (string, error) {
+	prizes, err := poker.ICM(req.Stacks, req.Payouts)
+	if err != nil {
+		return "", err
+	}
+
+	var chips, pool float64
+	for _, s := range req.Stacks {
+		chips += s
+	}
+	for _, p := range req.Payouts {
+		pool += p
+	}
+
+	var b strings.Builder
+	b.WriteString("Эквити по ICM:\n")
+	for i, s := range req.Stacks {
+		fmt.Fprintf(&b, "%d. Стек %s (%.2f%% фишек) — %s (%.2f%% призовых)\n",
+			i+1, formatAmount(s), s/chips*100, formatAmount(prizes[i]), prizes[i]/pool*100)
+	}
+	fmt.Fprintf(&b, "Выплаты: %s\n", amountsDisplay(req.Payouts))
+
+	if !req.HasDecision() {
+		return b.String(), nil
+	}
+	advice, err := poker.AdviseAllIn(poker.AllInDecision{
+		Stacks:  req.Stacks,
+		Payouts: req.Payouts,
+		Hero:    req.Hero - 1,
+		Villain: req.Villain - 1,
+		Pot:     req.Pot,
+		Equity:  equity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(&b, "\nОлл-ин: игрок %d против игрока %d", req.Hero, req.Villain)
+	if req.Pot > 0 {
+		fmt.Fprintf(&b, ", в банке %s", formatAmount(req.Pot))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Ваше эквити: %.2f%%", equity)
+	if len(req.Hand) > 0 {
+		fmt.Fprintf(&b, " (%s против ", CardsToText(req.Hand))
+		if req.Range.IsEmpty() {
+			b.WriteString("любых двух карт)")
+		} else {
+			fmt.Fprintf(&b, "%s)", req.Range)
+		}
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Нужное эквити по ICM: %.2f%% (по фишкам: %.2f%%)\n", advice.RequiredEquity, advice.ChipRequiredEquity)
+	fmt.Fprintf(&b, "Призовые после фолда: %s, после олл-ина: %s\n", formatAmount(advice.FoldEV), formatAmount(advice.CallEV))
+	if advice.CallEV >= advice.FoldEV {
+		b.WriteString("Рекомендация: олл-ин\n")
+	} else {
+		b.WriteString("Рекомендация: фолд\n")
+	}
+	return b.String(), nil
+}
+
+// parseAmounts reads a list of chip amounts separated by spaces, slashes or
+// commas followed by a space, so "12,5" still reads as a decimal.
+func parseAmounts(value string) ([]float64, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '/' || unicode.IsSpace(r)
+	})
+	var amounts []float64
+	for _, f := range fields {
+		f = strings.TrimRight(f, ",")
+		if f == "" {
+			continue
+		}
+		amount, err := parseAmount(f)
+		if err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount)
+	}
+	if len(amounts) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+	return amounts, nil
+}
+
+// parsePercent reads a percentage such as "45", "45%" or "45,5".
+func parsePercent(value string) (float64, error) {
+	cleaned := strings.TrimSpace(strings.NewReplacer("%", "", ",", ".").Replace(value))
+	percent, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || percent < 0 {
+		return 0, fmt.Errorf("invalid percentage: %s", value)
+	}
+	return percent, nil
+}
+
+func amountsDisplay(amounts []float64) string {
+	parts := make([]string, len(amounts))
+	for i, a := range amounts {
+		parts[i] = formatAmount(a)
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseICMRequest(t *testing.T) {
+	req, err := ParseICMRequest("stacks: 1500, 2300, 800 выплаты: 50/30/20")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Stacks) != 3 || req.Stacks[1] != 2300 || len(req.Payouts) != 3 || req.Payouts[2] != 20 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if req.HasDecision() || req.NeedsEquity() {
+		t.Fatalf("expected no decision, got %+v", req)
+	}
+
+	req, err = ParseICMRequest("stacks: 1500 2300 800 payouts: 50 30 20 hero: 3 villain: 2 pot: 150 equity: Ah 9h vs 22+, A2s+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Hero != 3 || req.Villain != 2 || req.Pot != 150 || len(req.Hand) != 2 || req.Range.IsEmpty() || !req.NeedsEquity() {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	eq := req.EquityRequest()
+	if eq.Players != 2 || len(eq.Hand) != 2 || eq.Range.String() != req.Range.String() {
+		t.Fatalf("unexpected equity request %+v", eq)
+	}
+
+	req, err = ParseICMRequest("stacks: 1500 2300 payouts: 100 hero: 1 villain: 2 эквити: 45,5%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Equity != 45.5 || req.NeedsEquity() {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
+
+func TestParseICMRequestErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"1500 2300 payouts: 100",
+		"stacks: 1500 payouts: 100",
+		"stacks: 1500 2300",
+		"stacks: 1500 abc payouts: 100",
+		"stacks: 1500 2300 payouts: 100 hero: 1 villain: 1 equity: 40",
+		"stacks: 1500 2300 payouts: 100 hero: 1 villain: 3 equity: 40",
+		"stacks: 1500 2300 payouts: 100 hero: 1 villain: 2",
+		"stacks: 1500 2300 payouts: 100 hero: 1 villain: 2 equity: 140",
+		"stacks: 1500 2300 payouts: 100 hero: 1 villain: 2 hand: Ah",
+	} {
+		if _, err := ParseICMRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestFormatICMResult(t *testing.T) {
+	req, err := ParseICMRequest("stacks: 1000 1000 1000 payouts: 50 50 hero: 1 villain: 3 pot: 100 equity: 60")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, err := FormatICMResult(req, req.Equity)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, fragment := range []string{
+		"1. Стек 1000 (33.33% фишек) — 33.33 (33.33% призовых)",
+		"Выплаты: 50 / 50",
+		"Олл-ин: игрок 1 против игрока 3, в банке 100",
+		"по фишкам: 47.62%",
+		"Рекомендация: фолд",
+	} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+
+	req.Hero, req.Villain = 0, 0
+	text, err = FormatICMResult(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(text, "Олл-ин") {
+		t.Fatalf("expected no decision, got: %s", text)
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"sync"
+)
+
+// Jobs tracks the running simulation of each chat so it can be cancelled from
+// another update. It is safe for concurrent use.
+type Jobs struct {
+	mu      sync.Mutex
+	next    int
+	running map[int64]job
+}
+
+type job struct {
+	id     int
+	cancel context.CancelFunc
+}
+
+// NewJobs returns an empty registry.
+func NewJobs() *Jobs {
+	return &Jobs{running: make(map[int64]job)}
+}
+
+// Start registers a simulation for the chat and returns its context together
+// with a function that must be called once the simulation ends. It reports
+// false when the chat already has a simulation running.
+func (j *Jobs) Start(parent context.Context, chatID int64) (context.Context, func(), bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, busy := j.running[chatID]; busy {
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithCancel(parent)
+	j.next++
+	id := j.next
+	j.running[chatID] = job{id: id, cancel: cancel}
+
+	done := func() {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if current, ok := j.running[chatID]; ok && current.id == id {
+			delete(j.running, chatID)
+		}
+		cancel()
+	}
+	return ctx, done, true
+}
+
+// Cancel stops the chat's running simulation and reports whether there was one.
+func (j *Jobs) Cancel(chatID int64) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	current, ok := j.running[chatID]
+	if ok {
+		current.cancel()
+		delete(j.running, chatID)
+	}
+	return ok
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJobs(t *testing.T) {
+	jobs := NewJobs()
+	ctx, done, ok := jobs.Start(context.Background(), 1)
+	if !ok {
+		t.Fatal("expected the first job to start")
+	}
+	if _, _, ok := jobs.Start(context.Background(), 1); ok {
+		t.Fatal("expected a second job in the same chat to be rejected")
+	}
+	if _, otherDone, ok := jobs.Start(context.Background(), 2); !ok {
+		t.Fatal("expected jobs in other chats to start")
+	} else {
+		otherDone()
+	}
+
+	if !jobs.Cancel(1) {
+		t.Fatal("expected the running job to be cancelled")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected the job context to be cancelled")
+	}
+	if jobs.Cancel(1) {
+		t.Fatal("expected nothing left to cancel")
+	}
+
+	// A new job started after the cancellation outlives the old done call.
+	_, newDone, ok := jobs.Start(context.Background(), 1)
+	if !ok {
+		t.Fatal("expected a new job after cancellation")
+	}
+	done()
+	if !jobs.Cancel(1) {
+		t.Fatal("expected the old done call to leave the new job registered")
+	}
+	newDone()
+}
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -15,20 +16,46 @@ const (
 	CallbackSetBoard   = "set_board"
 	CallbackSetTrials  = "set_trials"
 	CallbackSetStyle   = "set_style"
-	CallbackSimulate   = "simulate"
-	CallbackCancel     = "cancel"
+	// CallbackStylePercent moves the custom percentage slider.
+	CallbackStylePercent = "style_pct"
+	CallbackSetRange     = "set_range"
+	CallbackSetSeats     = "set_seats"
+	CallbackSetGame      = "set_game"
+	CallbackSetPot       = "set_pot"
+	CallbackSetDead      = "set_dead"
+	CallbackSimulate     = "simulate"
+	CallbackCancel       = "cancel"
+	// CallbackStopSimulation cancels a running simulation.
+	CallbackStopSimulation = "stop_simulation"
+
+	CallbackSetStacks    = "icm_stacks"
+	CallbackSetPayouts   = "icm_payouts"
+	CallbackSetAllIn     = "icm_allin"
+	CallbackSetEquity    = "icm_equity"
+	CallbackICMCalculate = "icm_calculate"
 )
 
 // MenuKeyboard returns inline keyboard markup for the interactive builder.
 func MenuKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Игра", CallbackSetGame),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Карты", CallbackSetHand),
 			tgbotapi.NewInlineKeyboardButtonData("Игроки", CallbackSetPlayers),
 			tgbotapi.NewInlineKeyboardButtonData("Стиль", CallbackSetStyle),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Стили по местам", CallbackSetSeats),
+			tgbotapi.NewInlineKeyboardButtonData("Банк и ставка", CallbackSetPot),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Борд", CallbackSetBoard),
+			tgbotapi.NewInlineKeyboardButtonData("Мёртвые карты", CallbackSetDead),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Диапазон", CallbackSetRange),
 			tgbotapi.NewInlineKeyboardButtonData("Симуляции", CallbackSetTrials),
 		),
 		tgbotapi.NewInlineKeyboardRow(
@@ -43,11 +70,16 @@ func SessionSummary(s Session) string {
 	var b strings.Builder
 	b.WriteString("Конструктор запроса\n")
 	b.WriteString("Выберите параметры кнопками ниже.\n\n")
+	b.WriteString(formatSessionLine("Игра", gameDisplay(s.Request.Game)))
 	b.WriteString(formatSessionLine("Карты", cardsDisplay(s.Request.Hand)))
 	b.WriteString(formatSessionLine("Игроки", playersDisplay(s.Request.Players)))
 	b.WriteString(formatSessionLine("Стиль", styleDisplay(s.Request.Style)))
+	b.WriteString(formatSessionLine("Стили по местам", seatStylesDisplay(s.Request.Styles)))
+	b.WriteString(formatSessionLine("Диапазон", rangeDisplay(s.Request.Range)))
 	b.WriteString(formatSessionLine("Борд", cardsDisplay(s.Request.Board)))
+	b.WriteString(formatSessionLine("Мёртвые карты", cardsDisplay(s.Request.Dead)))
 	b.WriteString(formatSessionLine("Симуляций", trialsDisplay(s.Request.Trials)))
+	b.WriteString(formatSessionLine("Банк / колл / стек", betDisplay(s.Request)))
 	b.WriteString("\nНажмите \"Запустить\", чтобы рассчитать вероятность.")
 	return b.String()
 }
@@ -70,13 +102,100 @@ func playersDisplay(players int) string {
 	return fmt.Sprintf("%d", players)
 }
 
+func seatStylesDisplay(styles []poker.PlayerStyle) string {
+	if len(styles) == 0 {
+		return "одинаковые"
+	}
+	return stylesDisplay(styles)
+}
+
+func rangeDisplay(r poker.Range) string {
+	if r.IsEmpty() {
+		return "не задан (по стилю)"
+	}
+	return r.String()
+}
+
+func betDisplay(req Request) string {
+	if req.Call == 0 {
+		return "не задано"
+	}
+	stack := "не задан"
+	if req.Stack > 0 {
+		stack = formatAmount(req.Stack)
+	}
+	return fmt.Sprintf("%s / %s / %s", formatAmount(req.Pot), formatAmount(req.Call), stack)
+}
+
 func trialsDisplay(trials int) string {
 	if trials == 0 {
-		return "по умолчанию (7000)"
+		return fmt.Sprintf("по умолчанию (%d)", DefaultTrials)
 	}
 	return fmt.Sprintf("%d", trials)
 }
 
+// ICMKeyboard returns inline keyboard markup for the ICM builder.
+func ICMKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Стеки", CallbackSetStacks),
+			tgbotapi.NewInlineKeyboardButtonData("Выплаты", CallbackSetPayouts),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Олл-ин", CallbackSetAllIn),
+			tgbotapi.NewInlineKeyboardButtonData("Эквити", CallbackSetEquity),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Рассчитать", CallbackICMCalculate),
+			tgbotapi.NewInlineKeyboardButtonData("Отмена", CallbackCancel),
+		),
+	)
+}
+
+// ICMSummary renders the current ICM builder values for the user.
+func ICMSummary(s Session) string {
+	var b strings.Builder
+	b.WriteString("Калькулятор ICM\n")
+	b.WriteString("Задайте стеки и выплаты, а для решения пуш/колл — олл-ин и эквити.\n\n")
+	b.WriteString(formatSessionLine("Стеки", amountsOrUnset(s.ICM.Stacks)))
+	b.WriteString(formatSessionLine("Выплаты", amountsOrUnset(s.ICM.Payouts)))
+	b.WriteString(formatSessionLine("Олл-ин", allInDisplay(s.ICM)))
+	b.WriteString(formatSessionLine("Эквити", equityDisplay(s.ICM)))
+	b.WriteString("\nНажмите \"Рассчитать\", чтобы получить эквити по ICM.")
+	return b.String()
+}
+
+func amountsOrUnset(amounts []float64) string {
+	if len(amounts) == 0 {
+		return "не задано"
+	}
+	return amountsDisplay(amounts)
+}
+
+func allInDisplay(req ICMRequest) string {
+	if !req.HasDecision() {
+		return "не задан"
+	}
+	text := fmt.Sprintf("игрок %d против игрока %d", req.Hero, req.Villain)
+	if req.Pot > 0 {
+		text += ", банк " + formatAmount(req.Pot)
+	}
+	return text
+}
+
+func equityDisplay(req ICMRequest) string {
+	switch {
+	case len(req.Hand) > 0 && req.Range.IsEmpty():
+		return CardsToText(req.Hand)
+	case len(req.Hand) > 0:
+		return fmt.Sprintf("%s против %s", CardsToText(req.Hand), req.Range)
+	case req.hasEquity:
+		return fmt.Sprintf("%.2f%%", req.Equity)
+	default:
+		return "не задано"
+	}
+}
+
 // StyleKeyboard enumerates style options.
 func StyleKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
@@ -85,10 +204,62 @@ func StyleKeyboard() tgbotapi.InlineKeyboardMarkup {
 			tgbotapi.NewInlineKeyboardButtonData("Тайтовый", styleCallback(pokerStyleTight)),
 			tgbotapi.NewInlineKeyboardButtonData("Лузовый", styleCallback(pokerStyleLoose)),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Любые две карты", styleCallback(pokerStyleAny)),
+			tgbotapi.NewInlineKeyboardButtonData("Свой процент рук", stylePercentCallback(DefaultStylePercent)),
+		),
+	)
+}
+
+// DefaultStylePercent is where the custom percentage slider starts.
+const DefaultStylePercent = 20
+
+// StylePercentKeyboard is a slider for a custom top-percent style: the
+// buttons move the value and the last row selects it.
+func StylePercentKeyboard(percent int) tgbotapi.InlineKeyboardMarkup {
+	var steps []tgbotapi.InlineKeyboardButton
+	for _, step := range []int{-10, -1, 1, 10} {
+		next := min(max(percent+step, 1), 100)
+		steps = append(steps, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%+d", step), stylePercentCallback(next)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		steps,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("Выбрать топ %d%%", percent), styleCallback(fmt.Sprintf("%d%%", percent))),
+		),
 	)
 }
 
+// StylePercentText describes the slider value above StylePercentKeyboard.
+func StylePercentText(percent int) string {
+	return fmt.Sprintf("Соперники играют топ %d%% стартовых рук.\n%s", percent, percentBar(percent))
+}
+
+// percentBar draws the slider position as a ten-step bar.
+func percentBar(percent int) string {
+	filled := (percent + 5) / 10
+	return "[" + strings.Repeat("■", filled) + strings.Repeat("□", 10-filled) + "]"
+}
+
+func stylePercentCallback(percent int) string {
+	return fmt.Sprintf("%s:%d", CallbackStylePercent, percent)
+}
+
+// ParseStylePercentCallback returns the slider value carried by callback data.
+func ParseStylePercentCallback(data string) (int, bool) {
+	value, ok := strings.CutPrefix(data, CallbackStylePercent+":")
+	if !ok {
+		return 0, false
+	}
+	percent, err := strconv.Atoi(value)
+	if err != nil || percent < 1 || percent > 100 {
+		return 0, false
+	}
+	return percent, true
+}
+
 const (
+	pokerStyleAny      = "any"
 	pokerStyleBalanced = "balanced"
 	pokerStyleTight    = "tight"
 	pokerStyleLoose    = "loose"
@@ -101,13 +272,69 @@ func styleCallback(val string) string {
 // ParseStyleCallback maps callback data to player styles.
 func ParseStyleCallback(data string) (poker.PlayerStyle, bool) {
 	switch data {
+	case styleCallback(pokerStyleAny):
+		return poker.StyleAny, true
 	case styleCallback(pokerStyleBalanced):
 		return poker.StyleBalanced, true
 	case styleCallback(pokerStyleTight):
 		return poker.StyleTight, true
 	case styleCallback(pokerStyleLoose):
 		return poker.StyleLoose, true
+	}
+	if value, ok := strings.CutPrefix(data, CallbackSetStyle+":"); ok && strings.HasSuffix(value, "%") {
+		if style, err := parseStyle(value); err == nil {
+			return style, true
+		}
+	}
+	return poker.StyleAny, false
+}
+
+// StopSimulationKeyboard is attached to the progress message of a running simulation.
+func StopSimulationKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Остановить расчёт", CallbackStopSimulation),
+		),
+	)
+}
+
+// GameKeyboard enumerates the supported variants.
+func GameKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Холдем", gameCallback(gameHoldem)),
+			tgbotapi.NewInlineKeyboardButtonData("PLO4", gameCallback(gameOmaha4)),
+			tgbotapi.NewInlineKeyboardButtonData("PLO5", gameCallback(gameOmaha5)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Шорт-дек (6+)", gameCallback(gameShortDeck)),
+		),
+	)
+}
+
+const (
+	gameHoldem    = "holdem"
+	gameOmaha4    = "plo4"
+	gameOmaha5    = "plo5"
+	gameShortDeck = "shortdeck"
+)
+
+func gameCallback(val string) string {
+	return CallbackSetGame + ":" + val
+}
+
+// ParseGameCallback maps callback data to game variants.
+func ParseGameCallback(data string) (poker.Game, bool) {
+	switch data {
+	case gameCallback(gameHoldem):
+		return poker.GameHoldem, true
+	case gameCallback(gameOmaha4):
+		return poker.GameOmaha4, true
+	case gameCallback(gameOmaha5):
+		return poker.GameOmaha5, true
+	case gameCallback(gameShortDeck):
+		return poker.GameShortDeck, true
 	default:
-		return poker.StyleBalanced, false
+		return poker.GameHoldem, false
 	}
 }
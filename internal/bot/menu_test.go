@@ -13,9 +13,12 @@ func TestParseStyleCallback(t *testing.T) {
 		valid bool
 		style poker.PlayerStyle
 	}{
+		{styleCallback(pokerStyleAny), true, poker.StyleAny},
 		{styleCallback(pokerStyleBalanced), true, poker.StyleBalanced},
 		{styleCallback(pokerStyleTight), true, poker.StyleTight},
 		{styleCallback(pokerStyleLoose), true, poker.StyleLoose},
+		{styleCallback("22%"), true, mustStyleTop(t, 22)},
+		{styleCallback("0%"), false, poker.StyleBalanced},
 		{"other", false, poker.StyleBalanced},
 	}
 
@@ -27,6 +30,61 @@ func TestParseStyleCallback(t *testing.T) {
 	}
 }
 
+func TestStylePercentKeyboard(t *testing.T) {
+	kb := StylePercentKeyboard(95)
+	var steps []int
+	for _, button := range kb.InlineKeyboard[0] {
+		percent, ok := ParseStylePercentCallback(*button.CallbackData)
+		if !ok {
+			t.Fatalf("unexpected slider callback %q", *button.CallbackData)
+		}
+		steps = append(steps, percent)
+	}
+	if want := []int{85, 94, 96, 100}; len(steps) != len(want) || steps[0] != want[0] || steps[1] != want[1] || steps[2] != want[2] || steps[3] != want[3] {
+		t.Fatalf("expected slider steps %v, got %v", want, steps)
+	}
+
+	style, ok := ParseStyleCallback(*kb.InlineKeyboard[1][0].CallbackData)
+	if !ok || style.Percent() != 95 {
+		t.Fatalf("expected the select button to pick the top 95%%, got %v", style.Percent())
+	}
+
+	for _, data := range []string{"style_pct:0", "style_pct:101", "style_pct:x", "set_style"} {
+		if _, ok := ParseStylePercentCallback(data); ok {
+			t.Fatalf("expected %q to be rejected", data)
+		}
+	}
+}
+
+func mustStyleTop(t *testing.T, percent float64) poker.PlayerStyle {
+	t.Helper()
+	style, err := poker.StyleTop(percent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return style
+}
+
+func TestParseGameCallback(t *testing.T) {
+	cases := []struct {
+		data  string
+		valid bool
+		game  poker.Game
+	}{
+		{gameCallback(gameHoldem), true, poker.GameHoldem},
+		{gameCallback(gameOmaha4), true, poker.GameOmaha4},
+		{gameCallback(gameOmaha5), true, poker.GameOmaha5},
+		{CallbackSetGame, false, poker.GameHoldem},
+	}
+
+	for _, tc := range cases {
+		game, ok := ParseGameCallback(tc.data)
+		if ok != tc.valid || (ok && game != tc.game) {
+			t.Fatalf("unexpected result for %s", tc.data)
+		}
+	}
+}
+
 func TestSessionSummary(t *testing.T) {
 	sess := NewSession()
 	sess.Request.Hand = []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("Kh")}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pokerbot/internal/poker"
+)
+
+// OutsRequest captures an outs query sent with the /outs command.
+type OutsRequest struct {
+	Game  poker.Game
+	Hand  []poker.Card
+	Board []poker.Card
+	// Range constrains the opponent; empty means any two cards.
+	Range poker.Range
+}
+
+var outsKeyPattern = regexp.MustCompile(`(?i)(board|борд|стол|range|диапазон|рейндж|game|игра)\s*:`)
+
+// ParseOutsRequest parses text like "Ah Kh board: 9h 5h 2c range: QQ+".
+func ParseOutsRequest(text string) (OutsRequest, error) {
+	var req OutsRequest
+
+	keys := outsKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	handText := text
+	if len(keys) > 0 {
+		handText = text[:keys[0][0]]
+	}
+
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		switch key {
+		case "board", "борд", "стол":
+			board, err := parseCards(value)
+			if err != nil {
+				return OutsRequest{}, fmt.Errorf("board: %w", err)
+			}
+			if len(board) != 3 && len(board) != 4 {
+				return OutsRequest{}, fmt.Errorf("board: expected a flop or a turn, got %d cards", len(board))
+			}
+			req.Board = board
+		case "range", "диапазон", "рейндж":
+			r, err := poker.ParseRange(value)
+			if err != nil {
+				return OutsRequest{}, fmt.Errorf("range: %w", err)
+			}
+			req.Range = r
+		case "game", "игра":
+			game, ok := gameAliases[normalize(value)]
+			if !ok {
+				return OutsRequest{}, fmt.Errorf("unknown game: %s", value)
+			}
+			req.Game = game
+		}
+	}
+
+	hand, err := parseCards(handText)
+	if err != nil {
+		return OutsRequest{}, fmt.Errorf("hand: %w", err)
+	}
+	if req.Game.IsOmaha() {
+		return OutsRequest{}, fmt.Errorf("outs are not supported in Omaha")
+	}
+	if len(hand) != req.Game.HoleCards() {
+		return OutsRequest{}, fmt.Errorf("hand: expected %d cards, got %d", req.Game.HoleCards(), len(hand))
+	}
+	if len(req.Board) == 0 {
+		return OutsRequest{}, fmt.Errorf("board: specify the flop or the turn")
+	}
+	req.Hand = hand
+	return req, nil
+}
+
+// ToOutsConfig converts an outs request into the calculator configuration.
+func (r OutsRequest) ToOutsConfig() poker.OutsConfig {
+	return poker.OutsConfig{
+		Game:    r.Game,
+		Hero:    r.Hand,
+		Board:   r.Board,
+		Villain: r.Range,
+	}
+}
+
+// FormatOutsResult lists live and dead outs grouped by the hand they make.
+func FormatOutsResult(req OutsRequest, result poker.OutsResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Аутов: %d", result.Live)
+	if result.Dead > 0 {
+		fmt.Fprintf(&b, " (и ещё %d мёртвых)", result.Dead)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Попадание следующей картой: %.2f%%\n", result.NextCard)
+	if len(req.Board) == 3 {
+		fmt.Fprintf(&b, "Попадание к риверу: %.2f%%\n", result.ByRiver)
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "Ваши карты: %s\n", CardsToText(req.Hand))
+	fmt.Fprintf(&b, "Карты на столе: %s\n", CardsToText(req.Board))
+	if req.Range.IsEmpty() {
+		b.WriteString("Соперник: любые две карты\n")
+	} else {
+		fmt.Fprintf(&b, "Соперник: %s (%d комбо)\n", req.Range, req.Range.Len())
+	}
+
+	writeOuts(&b, "\nАуты:\n", result.Cards, poker.OutLive)
+	writeOuts(&b, "\nМёртвые ауты (улучшают руку, но соперник чаще впереди):\n", result.Cards, poker.OutDead)
+	return b.String()
+}
+
+// writeOuts prints the cards of one kind grouped by the hero's new hand,
+// strongest category and highest card first.
+func writeOuts(b *strings.Builder, title string, outs []poker.OutCard, kind poker.OutKind) {
+	var byCategory [poker.StraightFlush + 1][]poker.Card
+	found := false
+	for _, out := range outs {
+		if out.Kind == kind {
+			byCategory[out.Hand.Category] = append(byCategory[out.Hand.Category], out.Card)
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+
+	b.WriteString(title)
+	for category := poker.StraightFlush; category >= poker.HighCard; category-- {
+		if cards := byCategory[category]; len(cards) > 0 {
+			sort.SliceStable(cards, func(i, j int) bool { return cards[i].Rank > cards[j].Rank })
+			fmt.Fprintf(b, "%s — %s\n", CardsToText(cards), strings.ToLower(categoryDisplay(category)))
+		}
+	}
+}
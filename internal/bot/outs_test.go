@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+func TestParseOutsRequest(t *testing.T) {
+	req, err := ParseOutsRequest("Ah Kh board: 9h 5h 2c диапазон: 99+, AQ+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Hand) != 2 || len(req.Board) != 3 || req.Range.String() != "99+, AQ+" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	for _, text := range []string{
+		"Ah Kh",
+		"Ah board: 9h 5h 2c",
+		"Ah Kh board: 9h 5h",
+		"Ah Kh Qd Jd board: 9h 5h 2c game: plo",
+		"Ah Kh board: 9h 5h 2c range: ZZ",
+	} {
+		if _, err := ParseOutsRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestFormatOutsResult(t *testing.T) {
+	req, err := ParseOutsRequest("Ah Kh board: 9h 5h 2c Td range: 99")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := poker.CalculateOuts(req.ToOutsConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := FormatOutsResult(req, result)
+	for _, fragment := range []string{"Аутов: 7 (и ещё 8 мёртвых)", "Qh Jh 8h 7h 6h 4h 3h — флеш", "Ac Ad As Kc Kd Ks — пара", "Соперник: 99 (6 комбо)"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+	if strings.Contains(text, "к риверу") {
+		t.Fatalf("expected no river odds on the turn, got: %s", text)
+	}
+}
@@ -4,22 +4,54 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"pokerbot/internal/poker"
 )
 
 // Request captures user intent derived from the incoming message.
 type Request struct {
-	Hand    []poker.Card
-	Board   []poker.Card
+	Game  poker.Game
+	Hand  []poker.Card
+	Board []poker.Card
+	// Dead lists cards known to be out of play, such as a folded hand shown.
+	Dead    []poker.Card
 	Players int
 	Style   poker.PlayerStyle
 	Trials  int
+	// trialsSet records an explicit trial count, which bypasses the preflop
+	// table.
+	trialsSet bool
+	// Range, when set, replaces Style for every opponent.
+	Range poker.Range
+	// Styles optionally assigns a style to each opponent seat in order.
+	Styles []poker.PlayerStyle
+	// Precision (in percentage points) or TimeBudget, when set, replace Trials
+	// and let the simulator stop on its own.
+	Precision  float64
+	TimeBudget time.Duration
+	// Pot (including the bet), Call and the effective Stack, when set, add a
+	// call/fold recommendation to the result. Stack is optional.
+	Pot   float64
+	Call  float64
+	Stack float64
+	// Fresh forces a new simulation for spots covered by the preflop table.
+	Fresh bool
 }
 
+// DefaultTrials is the number of Monte Carlo trials used when the user does not specify one.
+const DefaultTrials = 100000
+
+// MaxTimeBudget bounds how long a single request may simulate.
+const MaxTimeBudget = 30 * time.Second
+
 var styleAliases = map[string]poker.PlayerStyle{
+	"any":              poker.StyleAny,
+	"random":           poker.StyleAny,
+	"default":          poker.StyleAny,
+	"любые":            poker.StyleAny,
 	"balanced":         poker.StyleBalanced,
-	"default":          poker.StyleBalanced,
 	"neutral":          poker.StyleBalanced,
 	"сбалансированный": poker.StyleBalanced,
 	"tight":            poker.StyleTight,
@@ -30,10 +62,30 @@ var styleAliases = map[string]poker.PlayerStyle{
 	"луз":              poker.StyleLoose,
 }
 
+var gameAliases = map[string]poker.Game{
+	"holdem":     poker.GameHoldem,
+	"hold'em":    poker.GameHoldem,
+	"nlh":        poker.GameHoldem,
+	"холдем":     poker.GameHoldem,
+	"plo":        poker.GameOmaha4,
+	"plo4":       poker.GameOmaha4,
+	"omaha":      poker.GameOmaha4,
+	"омаха":      poker.GameOmaha4,
+	"plo5":       poker.GameOmaha5,
+	"omaha5":     poker.GameOmaha5,
+	"омаха5":     poker.GameOmaha5,
+	"shortdeck":  poker.GameShortDeck,
+	"short deck": poker.GameShortDeck,
+	"short":      poker.GameShortDeck,
+	"6+":         poker.GameShortDeck,
+	"шортдек":    poker.GameShortDeck,
+	"шорт-дек":   poker.GameShortDeck,
+}
+
 // ParseRequest parses a human-friendly multi-line message into a structured request.
 func ParseRequest(text string) (Request, error) {
 	lines := strings.Split(text, "\n")
-	req := Request{Style: poker.StyleBalanced, Trials: 7000}
+	req := Request{Style: poker.StyleAny, Trials: DefaultTrials}
 
 	for _, line := range lines {
 		parts := strings.SplitN(line, ":", 2)
@@ -50,10 +102,13 @@ func ParseRequest(text string) (Request, error) {
 			if err != nil {
 				return Request{}, fmt.Errorf("hand: %w", err)
 			}
-			if len(hand) != 2 {
-				return Request{}, fmt.Errorf("hand: expected 2 cards, got %d", len(hand))
-			}
 			req.Hand = hand
+		case "game", "игра":
+			game, ok := gameAliases[normalize(value)]
+			if !ok {
+				return Request{}, fmt.Errorf("unknown game: %s", value)
+			}
+			req.Game = game
 		case "board", "борд", "стол":
 			board, err := parseCards(value)
 			if err != nil {
@@ -63,6 +118,12 @@ func ParseRequest(text string) (Request, error) {
 				return Request{}, fmt.Errorf("board: expected up to 5 cards, got %d", len(board))
 			}
 			req.Board = board
+		case "dead", "мёртвые", "мертвые":
+			dead, err := parseCards(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("dead: %w", err)
+			}
+			req.Dead = dead
 		case "players", "игроков", "игроки":
 			num, err := parseInt(value)
 			if err != nil {
@@ -73,12 +134,23 @@ func ParseRequest(text string) (Request, error) {
 			}
 			req.Players = num
 		case "style", "стиль":
-			style := normalize(value)
-			if mapped, ok := styleAliases[style]; ok {
-				req.Style = mapped
-			} else {
-				return Request{}, fmt.Errorf("unknown style: %s", value)
+			style, err := parseStyle(value)
+			if err != nil {
+				return Request{}, err
+			}
+			req.Style = style
+		case "styles", "стили":
+			styles, err := parseStyles(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("styles: %w", err)
 			}
+			req.Styles = styles
+		case "range", "диапазон", "рейндж":
+			r, err := poker.ParseRange(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("range: %w", err)
+			}
+			req.Range = r
 		case "trials", "симуляций":
 			num, err := parseInt(value)
 			if err != nil {
@@ -87,16 +159,80 @@ func ParseRequest(text string) (Request, error) {
 			if num < 500 {
 				return Request{}, fmt.Errorf("trials: value must be >= 500 for stability")
 			}
-			req.Trials = num
+			req.Trials, req.trialsSet = num, true
+		case "precision", "точность":
+			precision, err := parsePrecision(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("precision: %w", err)
+			}
+			req.Precision = precision
+		case "time", "время":
+			budget, err := parseTimeBudget(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("time: %w", err)
+			}
+			req.TimeBudget = budget
+		case "pot", "банк", "пот":
+			amount, err := parseAmount(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("pot: %w", err)
+			}
+			req.Pot = amount
+		case "call", "колл":
+			amount, err := parseAmount(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("call: %w", err)
+			}
+			req.Call = amount
+		case "fresh", "заново":
+			fresh, err := parseBool(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("fresh: %w", err)
+			}
+			req.Fresh = fresh
+		case "stack", "стек":
+			amount, err := parseAmount(value)
+			if err != nil {
+				return Request{}, fmt.Errorf("stack: %w", err)
+			}
+			req.Stack = amount
 		}
 	}
 
-	if len(req.Hand) != 2 {
-		return Request{}, fmt.Errorf("hand: two cards are required")
+	if len(req.Hand) != req.Game.HoleCards() {
+		return Request{}, fmt.Errorf("hand: %s requires %d cards, got %d", req.Game, req.Game.HoleCards(), len(req.Hand))
+	}
+	if err := checkDeck(req.Game, req.Hand); err != nil {
+		return Request{}, fmt.Errorf("hand: %w", err)
+	}
+	if err := checkDeck(req.Game, req.Board); err != nil {
+		return Request{}, fmt.Errorf("board: %w", err)
+	}
+	if err := checkDeck(req.Game, req.Dead); err != nil {
+		return Request{}, fmt.Errorf("dead: %w", err)
+	}
+	if err := checkDistinct(req.Hand, req.Board, req.Dead); err != nil {
+		return Request{}, err
+	}
+	if !req.Range.IsEmpty() && req.Game.IsOmaha() {
+		return Request{}, fmt.Errorf("range: ranges are not supported in Omaha")
+	}
+	if len(req.Styles) > 0 {
+		if !req.Range.IsEmpty() {
+			return Request{}, fmt.Errorf("styles: cannot be combined with range")
+		}
+		if req.Players == 0 {
+			req.Players = len(req.Styles) + 1
+		} else if req.Players-1 != len(req.Styles) {
+			return Request{}, fmt.Errorf("styles: expected %d styles for %d opponents, got %d", req.Players-1, req.Players-1, len(req.Styles))
+		}
 	}
 	if req.Players == 0 {
 		return Request{}, fmt.Errorf("players: specify number of players at the table")
 	}
+	if err := checkBet(req.Pot, req.Call, req.Stack); err != nil {
+		return Request{}, err
+	}
 
 	return req, nil
 }
@@ -118,6 +254,139 @@ func parseCards(value string) ([]poker.Card, error) {
 	return cards, nil
 }
 
+// checkDeck rejects cards stripped from the game's deck, such as deuces in Short Deck.
+func checkDeck(game poker.Game, cards []poker.Card) error {
+	for _, c := range cards {
+		if !game.HasCard(c) {
+			return fmt.Errorf("card %s is not in the %s deck", c, game)
+		}
+	}
+	return nil
+}
+
+// checkDistinct rejects a card listed twice across the hand, board and dead cards.
+func checkDistinct(groups ...[]poker.Card) error {
+	seen := make(map[poker.Card]bool)
+	for _, group := range groups {
+		for _, c := range group {
+			if seen[c] {
+				return fmt.Errorf("card %s is listed twice", c)
+			}
+			seen[c] = true
+		}
+	}
+	return nil
+}
+
+// parseStyle reads a named style or a custom share of hands such as "22%".
+func parseStyle(value string) (poker.PlayerStyle, error) {
+	if mapped, ok := styleAliases[normalize(value)]; ok {
+		return mapped, nil
+	}
+	if number, ok := strings.CutSuffix(strings.TrimSpace(value), "%"); ok {
+		percent, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(number), ",", "."), 64)
+		if err != nil {
+			return poker.StyleBalanced, fmt.Errorf("invalid style percentage: %s", value)
+		}
+		return poker.StyleTop(percent)
+	}
+	return poker.StyleBalanced, fmt.Errorf("unknown style: %s", value)
+}
+
+// parseStyles reads a comma- or space-separated list of per-seat styles.
+func parseStyles(value string) ([]poker.PlayerStyle, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+	if len(fields) > 8 {
+		return nil, fmt.Errorf("at most 8 opponents are supported")
+	}
+
+	styles := make([]poker.PlayerStyle, 0, len(fields))
+	for _, f := range fields {
+		style, err := parseStyle(f)
+		if err != nil {
+			return nil, err
+		}
+		styles = append(styles, style)
+	}
+	return styles, nil
+}
+
+// parsePrecision reads a target margin such as "0.5", "±0,5%" or "1%".
+func parsePrecision(value string) (float64, error) {
+	cleaned := strings.NewReplacer("±", "", "%", "", ",", ".").Replace(strings.TrimSpace(value))
+	precision, err := strconv.ParseFloat(strings.TrimSpace(cleaned), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s", value)
+	}
+	if precision < 0.05 || precision > 5 {
+		return 0, fmt.Errorf("value must be between 0.05 and 5")
+	}
+	return precision, nil
+}
+
+// parseTimeBudget reads a duration such as "5s", "1.5s" or a plain number of seconds.
+func parseTimeBudget(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	budget, err := time.ParseDuration(trimmed)
+	if err != nil {
+		seconds, convErr := parseInt(trimmed)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		budget = time.Duration(seconds) * time.Second
+	}
+	if budget <= 0 || budget > MaxTimeBudget {
+		return 0, fmt.Errorf("value must be between 0 and %v", MaxTimeBudget)
+	}
+	return budget, nil
+}
+
+// parseAmount reads a non-negative chip amount such as "120" or "12,5".
+func parseAmount(value string) (float64, error) {
+	parts := strings.Fields(strings.ReplaceAll(value, ",", "."))
+	if len(parts) == 0 {
+		return 0, fmt.Errorf("missing value")
+	}
+	amount, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid amount: %s", parts[0])
+	}
+	return amount, nil
+}
+
+func parseBool(value string) (bool, error) {
+	switch normalize(value) {
+	case "yes", "true", "on", "1", "да":
+		return true, nil
+	case "no", "false", "off", "0", "нет":
+		return false, nil
+	default:
+		return false, fmt.Errorf("expected yes or no, got %s", value)
+	}
+}
+
+// checkBet validates the pot odds inputs: pot and call go together and the
+// pot includes the bet to call.
+func checkBet(pot, call, stack float64) error {
+	switch {
+	case pot == 0 && call == 0:
+		if stack > 0 {
+			return fmt.Errorf("stack: specify pot and call as well")
+		}
+		return nil
+	case call == 0:
+		return fmt.Errorf("call: specify the bet to call")
+	case pot < call:
+		return fmt.Errorf("pot: must include the bet to call")
+	}
+	return nil
+}
+
 func parseInt(value string) (int, error) {
 	parts := strings.Fields(value)
 	if len(parts) == 0 {
@@ -130,13 +399,46 @@ func normalize(s string) string {
 	return strings.TrimSpace(strings.ToLower(s))
 }
 
+// PreflopResult answers the request from the precomputed preflop table. It
+// reports false for spots the table does not cover: other games, a known
+// board or dead cards, ranges, per-seat styles, adaptive runs, an explicit
+// trial count or when Fresh is set.
+func (r Request) PreflopResult() (poker.SimulationResult, bool) {
+	if r.Fresh || r.Game != poker.GameHoldem || len(r.Board) > 0 || len(r.Dead) > 0 || !r.Range.IsEmpty() || len(r.Styles) > 0 ||
+		r.trialsSet || r.Precision > 0 || r.TimeBudget > 0 {
+		return poker.SimulationResult{}, false
+	}
+	return poker.LookupPreflop(r.Hand, r.Players-1, r.Style)
+}
+
 // ToSimulationConfig converts a bot request into a simulator configuration.
 func (r Request) ToSimulationConfig() poker.SimulationConfig {
-	return poker.SimulationConfig{
+	cfg := poker.SimulationConfig{
+		Game:      r.Game,
 		Hero:      r.Hand,
 		Board:     r.Board,
+		Dead:      r.Dead,
 		Opponents: r.Players - 1,
 		Style:     r.Style,
 		Trials:    r.Trials,
+		Precision: r.Precision,
+	}
+	if r.Precision > 0 || r.TimeBudget > 0 {
+		// Trials is left to the simulator's adaptive limit.
+		cfg.Trials = 0
+		cfg.TimeBudget = r.TimeBudget
+	}
+	if len(r.Styles) == 0 && r.Range.IsEmpty() {
+		return cfg
+	}
+
+	cfg.Seats = make([]poker.Seat, cfg.Opponents)
+	for i := range cfg.Seats {
+		cfg.Seats[i].Style = r.Style
+		if i < len(r.Styles) {
+			cfg.Seats[i].Style = r.Styles[i]
+		}
+		cfg.Seats[i].Range = r.Range
 	}
+	return cfg
 }
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"testing"
+	"time"
 
 	"pokerbot/internal/poker"
 )
@@ -49,3 +50,227 @@ func TestParseRequestErrors(t *testing.T) {
 		t.Fatal("expected error for unknown style")
 	}
 }
+
+func TestParseRequestRange(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nplayers: 3\nrange: QQ+, AKs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Range.Len() != 22 {
+		t.Fatalf("expected 22 combos, got %d", req.Range.Len())
+	}
+
+	cfg := req.ToSimulationConfig()
+	if len(cfg.Seats) != 2 || cfg.Seats[1].Range.Len() != 22 {
+		t.Fatalf("expected the range to apply to both opponents, got %+v", cfg.Seats)
+	}
+
+	if _, err := ParseRequest("hand: Ah Kh\nplayers: 2\nrange: QQ++"); err == nil {
+		t.Fatal("expected error for malformed range")
+	}
+}
+
+func TestParseRequestStyles(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nstyles: tight, loose, loose")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Players != 4 {
+		t.Fatalf("expected players to follow the seat styles, got %d", req.Players)
+	}
+
+	cfg := req.ToSimulationConfig()
+	want := []poker.PlayerStyle{poker.StyleTight, poker.StyleLoose, poker.StyleLoose}
+	if len(cfg.Seats) != len(want) {
+		t.Fatalf("expected %d seats, got %d", len(want), len(cfg.Seats))
+	}
+	for i, style := range want {
+		if cfg.Seats[i].Style != style {
+			t.Fatalf("seat %d: expected style %v, got %v", i, style, cfg.Seats[i].Style)
+		}
+	}
+
+	for text, want := range map[string]poker.PlayerStyle{
+		"hand: Ah Kh\nplayers: 3":                  poker.StyleAny,
+		"hand: Ah Kh\nplayers: 3\nstyle: любые":    poker.StyleAny,
+		"hand: Ah Kh\nplayers: 3\nstyle: random":   poker.StyleAny,
+		"hand: Ah Kh\nplayers: 3\nstyle: balanced": poker.StyleBalanced,
+	} {
+		req, err := ParseRequest(text)
+		if err != nil || req.Style != want {
+			t.Fatalf("expected style %v for %q, got %v (%v)", want, text, req.Style, err)
+		}
+	}
+
+	req, err = ParseRequest("hand: Ah Kh\nplayers: 3\nstyle: 22%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Style != mustStyleTop(t, 22) {
+		t.Fatalf("expected a top 22%% style, got %v", req.Style)
+	}
+	if _, err := ParseRequest("hand: Ah Kh\nplayers: 3\nstyle: 0%"); err == nil {
+		t.Fatal("expected error for an empty percentage")
+	}
+
+	if _, err := ParseRequest("hand: Ah Kh\nplayers: 3\nstyles: tight"); err == nil {
+		t.Fatal("expected error for style count mismatch")
+	}
+	if _, err := ParseRequest("hand: Ah Kh\nstyles: tight, loose\nrange: QQ+"); err == nil {
+		t.Fatal("expected error for styles combined with a range")
+	}
+	if _, err := ParseRequest("hand: Ah Kh\nstyles: tight, wild"); err == nil {
+		t.Fatal("expected error for unknown seat style")
+	}
+}
+
+func TestParseRequestOmaha(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Ad Kh Kd\ngame: plo\nplayers: 3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Game != poker.GameOmaha4 || len(req.Hand) != 4 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if cfg := req.ToSimulationConfig(); cfg.Game != poker.GameOmaha4 {
+		t.Fatalf("expected game to reach the simulator config")
+	}
+
+	if _, err := ParseRequest("game: plo5\nhand: Ah Ad Kh Kd\nplayers: 3"); err == nil {
+		t.Fatal("expected error for four cards in PLO5")
+	}
+	if _, err := ParseRequest("hand: Ah Ad Kh Kd\nplayers: 3"); err == nil {
+		t.Fatal("expected error for four cards in hold'em")
+	}
+	if _, err := ParseRequest("game: plo\nhand: Ah Ad Kh Kd\nplayers: 2\nrange: QQ+"); err == nil {
+		t.Fatal("expected error for ranges in Omaha")
+	}
+	if _, err := ParseRequest("game: stud\nhand: Ah Kh\nplayers: 2"); err == nil {
+		t.Fatal("expected error for unknown game")
+	}
+}
+
+func TestParseRequestShortDeck(t *testing.T) {
+	req, err := ParseRequest("game: shortdeck\nhand: Ah Kh\nboard: 6c 7d 9s\nplayers: 3\nrange: TT+")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Game != poker.GameShortDeck || req.Range.IsEmpty() {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	if _, err := ParseRequest("game: 6+\nhand: Ah 5h\nplayers: 2"); err == nil {
+		t.Fatal("expected error for a five in short deck")
+	}
+	if _, err := ParseRequest("hand: Ah Kh\nboard: 2c 7d 9s\nplayers: 2\nигра: шортдек"); err == nil {
+		t.Fatal("expected error for a deuce on the short deck board")
+	}
+}
+
+func TestParseRequestPrecisionAndTime(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nplayers: 3\nточность: ±0,5%\nвремя: 3s")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Precision != 0.5 || req.TimeBudget != 3*time.Second {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	cfg := req.ToSimulationConfig()
+	if cfg.Precision != 0.5 || cfg.TimeBudget != 3*time.Second || cfg.Trials != 0 {
+		t.Fatalf("expected an adaptive config, got %+v", cfg)
+	}
+
+	if req, err := ParseRequest("hand: Ah Kh\nplayers: 3\ntime: 2"); err != nil || req.TimeBudget != 2*time.Second {
+		t.Fatalf("expected plain seconds to parse, got %+v (%v)", req, err)
+	}
+	for _, text := range []string{
+		"hand: Ah Kh\nplayers: 3\nprecision: 0",
+		"hand: Ah Kh\nplayers: 3\nprecision: abc",
+		"hand: Ah Kh\nplayers: 3\ntime: 5m",
+	} {
+		if _, err := ParseRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestParseRequestPotOdds(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nplayers: 2\nboard: Qh 7h 2c\npot: 120\ncall: 40\nстек: 900")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Pot != 120 || req.Call != 40 || req.Stack != 900 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	for _, text := range []string{
+		"hand: Ah Kh\nplayers: 2\npot: 120",
+		"hand: Ah Kh\nplayers: 2\ncall: 40",
+		"hand: Ah Kh\nplayers: 2\npot: 20\ncall: 40",
+		"hand: Ah Kh\nplayers: 2\nstack: 900",
+		"hand: Ah Kh\nplayers: 2\npot: -5\ncall: 1",
+	} {
+		if _, err := ParseRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestParseRequestDeadCards(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nplayers: 3\nboard: Qh Jh 2c\nмёртвые: 7c 2d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Dead) != 2 {
+		t.Fatalf("expected two dead cards, got %v", req.Dead)
+	}
+	if cfg := req.ToSimulationConfig(); len(cfg.Dead) != 2 {
+		t.Fatalf("expected dead cards in the config, got %v", cfg.Dead)
+	}
+
+	for _, text := range []string{
+		"hand: Ah Kh\nplayers: 3\ndead: Ah 2d",
+		"hand: Ah Kh\nplayers: 3\nboard: Qh Jh 2c\ndead: 2c",
+		"hand: Ah Kh\nplayers: 3\ndead: 7c 7c",
+		"hand: Ah Kh\nplayers: 3\nboard: Qh Qh 2c",
+		"hand: Ah Kh\nplayers: 3\ngame: shortdeck\ndead: 2d",
+	} {
+		if _, err := ParseRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestRequestPreflopResult(t *testing.T) {
+	req, err := ParseRequest("hand: Ah Kh\nplayers: 3\nstyle: tight")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, ok := req.PreflopResult()
+	if !ok || res.Method != poker.MethodPrecomputed {
+		t.Fatalf("expected a table lookup, got %+v", res)
+	}
+
+	for _, text := range []string{
+		"hand: Ah Kh\nplayers: 3\nfresh: yes",
+		"hand: Ah Kh\nplayers: 3\nboard: 2c 7d 9s",
+		"hand: Ah Kh\nplayers: 3\ndead: 7c 2d",
+		"hand: Ah Kh\nplayers: 3\nrange: QQ+",
+		"hand: Ah Kh\nstyles: tight, loose",
+		"hand: Ah Kh\nplayers: 3\nprecision: 0.5",
+		"hand: Ah Kh\nplayers: 3\ntrials: 20000",
+		"hand: Ah Kh\nplayers: 10",
+		"hand: Ah Kh\nplayers: 3\ngame: shortdeck",
+	} {
+		req, err := ParseRequest(text)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", text, err)
+		}
+		if _, ok := req.PreflopResult(); ok {
+			t.Fatalf("expected a fresh simulation for %q", text)
+		}
+	}
+	if _, err := ParseRequest("hand: Ah Kh\nplayers: 3\nfresh: maybe"); err == nil {
+		t.Fatal("expected error for an invalid fresh flag")
+	}
+}
@@ -0,0 +1,470 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"pokerbot/internal/engine"
+)
+
+// Defaults of a match started with a bare /play.
+const (
+	DefaultPlayStack  = 1000
+	DefaultBigBlind   = 10
+	DefaultAggression = 0.5
+)
+
+const (
+	// CallbackPlayAction carries the user's action in a heads-up hand.
+	CallbackPlayAction = "play_act"
+	CallbackPlayNext   = "play_next"
+	CallbackPlayQuit   = "play_quit"
+)
+
+// Seats of a heads-up match.
+const (
+	PlayerSeat = 0
+	BotSeat    = 1
+)
+
+// ErrNotYourTurn rejects an action from a player who is not to act.
+var ErrNotYourTurn = errors.New("it is not your turn")
+
+// PlayRequest configures a heads-up match against the bot.
+type PlayRequest struct {
+	Stack      int
+	SmallBlind int
+	BigBlind   int
+	// Aggression from 0 to 1 sets how often and how big the bot bets.
+	Aggression float64
+}
+
+var playKeyPattern = regexp.MustCompile(`(?i)(stack|стек|blinds|блайнды|aggression|агрессия)\s*:`)
+
+var aggressionAliases = map[string]float64{
+	"passive":     0.2,
+	"пассивный":   0.2,
+	"normal":      DefaultAggression,
+	"обычный":     DefaultAggression,
+	"aggressive":  0.8,
+	"агрессивный": 0.8,
+}
+
+// ParsePlayRequest parses text like "stack: 1000 blinds: 5/10 aggression:
+// aggressive"; every key is optional.
+func ParsePlayRequest(text string) (PlayRequest, error) {
+	req := PlayRequest{Stack: DefaultPlayStack, SmallBlind: DefaultBigBlind / 2, BigBlind: DefaultBigBlind, Aggression: DefaultAggression}
+
+	keys := playKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(keys) == 0 && strings.TrimSpace(text) != "" || len(keys) > 0 && strings.TrimSpace(text[:keys[0][0]]) != "" {
+		return PlayRequest{}, fmt.Errorf("expected key: value pairs such as stack:, blinds: and aggression:")
+	}
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		var err error
+		switch key {
+		case "stack", "стек":
+			req.Stack, err = parseInt(value)
+		case "blinds", "блайнды":
+			req.SmallBlind, req.BigBlind, err = parseBlinds(value)
+		default:
+			req.Aggression, err = parseAggression(value)
+		}
+		if err != nil {
+			return PlayRequest{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if req.Stack < 2*req.BigBlind {
+		return PlayRequest{}, fmt.Errorf("stack: expected at least two big blinds")
+	}
+	return req, nil
+}
+
+// parseBlinds reads "5/10", or a single big blind with half of it as the
+// small blind.
+func parseBlinds(value string) (int, int, error) {
+	chips, err := parseChips(value)
+	if err != nil {
+		return 0, 0, err
+	}
+	switch {
+	case len(chips) == 1 && chips[0] > 0:
+		return max(chips[0]/2, 1), chips[0], nil
+	case len(chips) == 2 && chips[0] > 0 && chips[0] <= chips[1]:
+		return chips[0], chips[1], nil
+	}
+	return 0, 0, fmt.Errorf("expected the blinds as 5/10")
+}
+
+func parseAggression(value string) (float64, error) {
+	if a, ok := aggressionAliases[normalize(value)]; ok {
+		return a, nil
+	}
+	percent, err := parsePercent(value)
+	if err != nil || percent > 100 {
+		return 0, fmt.Errorf("expected passive, normal, aggressive or 0-100%%, got %s", value)
+	}
+	return percent / 100, nil
+}
+
+// HeadsUp is a match between the user and the bot. Stacks carry over from
+// hand to hand and the button alternates.
+type HeadsUp struct {
+	Request PlayRequest
+	Stacks  []int
+	Button  int
+	// Number counts the hands dealt and Hand holds the latest one.
+	Number int
+	Hand   *engine.Hand
+	// Turn counts the actions of the hand, so a stale button press or bot
+	// decision is ignored.
+	Turn int
+	// MessageID is the message showing the hand.
+	MessageID int
+	rng       *rand.Rand
+}
+
+// NewHeadsUp starts a match; the seed drives the deals and the bot's bluffs.
+func NewHeadsUp(req PlayRequest, seed int64) *HeadsUp {
+	return &HeadsUp{
+		Request: req,
+		Stacks:  []int{req.Stack, req.Stack},
+		Button:  BotSeat,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Over reports whether a player has lost every chip.
+func (g *HeadsUp) Over() bool {
+	return g.Stacks[PlayerSeat] == 0 || g.Stacks[BotSeat] == 0
+}
+
+// Deal starts the next hand with the button moved. When the bot acts first,
+// BotTurn returns its decision.
+func (g *HeadsUp) Deal() error {
+	if g.Over() {
+		return errors.New("the match is over")
+	}
+	if g.Hand != nil && !g.Hand.Done() {
+		return errors.New("the current hand is not over")
+	}
+	hand, err := engine.New(engine.Config{
+		Stacks:     g.Stacks,
+		Button:     1 - g.Button,
+		SmallBlind: g.Request.SmallBlind,
+		BigBlind:   g.Request.BigBlind,
+		Seed:       g.rng.Int63(),
+	})
+	if err != nil {
+		return err
+	}
+	g.Hand, g.Button, g.Turn = hand, 1-g.Button, 0
+	g.Number++
+	return nil
+}
+
+// Act applies the user's action; turn must match the match's turn counter.
+func (g *HeadsUp) Act(turn int, a engine.Action) error {
+	if g.Hand == nil || g.Hand.ToAct() != PlayerSeat || turn != g.Turn {
+		return ErrNotYourTurn
+	}
+	return g.apply(a)
+}
+
+// BotTurn is a decision the bot owes in a hand. Deciding only reads the
+// hand, so it may run outside the loop that applies actions to the match.
+type BotTurn struct {
+	Number   int
+	Turn     int
+	hand     *engine.Hand
+	opponent engine.Opponent
+	seed     int64
+}
+
+// BotTurn returns the decision the bot owes, or false when the user is to act
+// or the hand is over.
+func (g *HeadsUp) BotTurn() (BotTurn, bool) {
+	if g.Hand == nil || g.Hand.ToAct() != BotSeat {
+		return BotTurn{}, false
+	}
+	return BotTurn{
+		Number:   g.Number,
+		Turn:     g.Turn,
+		hand:     g.Hand,
+		opponent: engine.Opponent{Aggression: g.Request.Aggression},
+		seed:     g.rng.Int63(),
+	}, true
+}
+
+// Decide chooses the bot's action. When the bot cannot decide in time it
+// checks or folds, so the hand never stalls on its turn.
+func (t BotTurn) Decide(ctx context.Context) engine.Action {
+	a, err := t.opponent.Decide(ctx, t.hand, rand.New(rand.NewSource(t.seed)))
+	if err != nil {
+		a = engine.Action{Kind: engine.ActionFold}
+		if t.hand.Legal().Call == 0 {
+			a.Kind = engine.ActionCheck
+		}
+	}
+	return a
+}
+
+// ApplyBot applies the action decided for t. It reports ErrNotYourTurn when
+// the match has moved on since t was taken.
+func (g *HeadsUp) ApplyBot(t BotTurn, a engine.Action) error {
+	if g.Hand != t.hand || g.Number != t.Number || g.Turn != t.Turn || g.Hand.ToAct() != BotSeat {
+		return ErrNotYourTurn
+	}
+	return g.apply(a)
+}
+
+func (g *HeadsUp) apply(a engine.Action) error {
+	if err := g.Hand.Apply(a); err != nil {
+		return err
+	}
+	g.Turn++
+	if g.Hand.Done() {
+		g.Stacks = g.Hand.Stacks()
+	}
+	return nil
+}
+
+// FormatHeadsUp shows the table to the user: stacks, the user's cards, the
+// board and the bot's latest actions. A finished hand ends with a summary.
+func FormatHeadsUp(g *HeadsUp) string {
+	h := g.Hand
+	var b strings.Builder
+	fmt.Fprintf(&b, "Раздача #%d, блайнды %d/%d\n", g.Number, g.Request.SmallBlind, g.Request.BigBlind)
+	if board := h.Board(); len(board) > 0 {
+		fmt.Fprintf(&b, "Борд: %s\n", CardsToText(board))
+	}
+	fmt.Fprintf(&b, "Банк: %d\n\n", h.Pot())
+	for _, seat := range []int{BotSeat, PlayerSeat} {
+		p := h.Player(seat)
+		fmt.Fprintf(&b, "%s: стек %d", seatName(seat), p.Stack)
+		if p.Bet > 0 && !h.Done() {
+			fmt.Fprintf(&b, ", ставка %d", p.Bet)
+		}
+		if seat == h.Button() {
+			b.WriteString(" (баттон)")
+		}
+		if seat == PlayerSeat {
+			fmt.Fprintf(&b, ", карты %s", CardsToText(p.Hole))
+		}
+		b.WriteString("\n")
+	}
+
+	if actions := botActions(h); len(actions) > 0 {
+		fmt.Fprintf(&b, "\nБот: %s\n", strings.Join(actions, ", "))
+	}
+	switch h.ToAct() {
+	case PlayerSeat:
+		b.WriteString("\nВаш ход.")
+		return b.String()
+	case BotSeat:
+		b.WriteString("\nБот думает…")
+		return b.String()
+	}
+
+	res := h.Result()
+	b.WriteString("\n")
+	if res.Showdown {
+		for _, seat := range []int{BotSeat, PlayerSeat} {
+			fmt.Fprintf(&b, "%s: %s — %s\n", seatName(seat), CardsToText(h.Player(seat).Hole), HandDisplay(res.Hands[seat]))
+		}
+	}
+	net := res.Winnings[PlayerSeat] - h.Player(PlayerSeat).Contributed
+	switch {
+	case net > 0:
+		fmt.Fprintf(&b, "Вы выигрываете %d.\n", net)
+	case net < 0:
+		fmt.Fprintf(&b, "Бот выигрывает %d.\n", -net)
+	default:
+		b.WriteString("Банк разделён поровну.\n")
+	}
+	fmt.Fprintf(&b, "Стеки: вы %d, бот %d.", g.Stacks[PlayerSeat], g.Stacks[BotSeat])
+	switch {
+	case g.Stacks[BotSeat] == 0:
+		b.WriteString("\n\nУ бота не осталось фишек — вы победили! Новая игра: /play")
+	case g.Stacks[PlayerSeat] == 0:
+		b.WriteString("\n\nУ вас не осталось фишек. Новая игра: /play")
+	}
+	return b.String()
+}
+
+// FormatPlayQuit sums up a match the user ended.
+func FormatPlayQuit(g *HeadsUp) string {
+	return fmt.Sprintf("Игра окончена после %d раздач. Стеки: вы %d, бот %d (начинали с %d).",
+		g.Number, g.Stacks[PlayerSeat], g.Stacks[BotSeat], g.Request.Stack)
+}
+
+func seatName(seat int) string {
+	if seat == BotSeat {
+		return "Бот"
+	}
+	return "Вы"
+}
+
+// botActions lists what the bot did since the user last acted.
+func botActions(h *engine.Hand) []string {
+	log := h.Log()
+	var actions []string
+	for i := len(log) - 1; i >= 0 && log[i].Seat == BotSeat; i-- {
+		if log[i].Kind != engine.ActionPost {
+			actions = append(actions, eventDisplay(log[i]))
+		}
+	}
+	slices.Reverse(actions)
+	return actions
+}
+
+func eventDisplay(e engine.Event) string {
+	var text string
+	switch e.Kind {
+	case engine.ActionPost:
+		text = fmt.Sprintf("блайнд %d", e.Amount)
+	case engine.ActionFold:
+		text = "фолд"
+	case engine.ActionCheck:
+		text = "чек"
+	case engine.ActionCall:
+		text = fmt.Sprintf("колл %d", e.Amount)
+	case engine.ActionBet:
+		text = fmt.Sprintf("бет %d", e.To)
+	case engine.ActionRaise:
+		text = fmt.Sprintf("рейз до %d", e.To)
+	}
+	if e.AllIn {
+		text += " (олл-ин)"
+	}
+	return text
+}
+
+// PlayKeyboard offers the user's legal actions, or the next hand once the
+// hand is over; while the bot thinks only the quit button is left. It is empty
+// when the match has ended.
+func PlayKeyboard(g *HeadsUp) tgbotapi.InlineKeyboardMarkup {
+	h := g.Hand
+	quit := tgbotapi.NewInlineKeyboardButtonData("Закончить игру", CallbackPlayQuit)
+	if h.Done() {
+		if g.Over() {
+			return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+		}
+		return tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Следующая раздача", CallbackPlayNext), quit),
+		)
+	}
+
+	if h.ToAct() != PlayerSeat {
+		return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(quit))
+	}
+	rows := actionRows(h, CallbackPlayAction, g.Number, g.Turn)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(quit))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// actionRows lays out the legal actions of the player to act: fold and call
+// or check, then the bet sizes. The buttons carry actionCallback data under
+// prefix.
+func actionRows(h *engine.Hand, prefix string, number, turn int) [][]tgbotapi.InlineKeyboardButton {
+	legal := h.Legal()
+	button := func(label string, a engine.Action) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(label, actionCallback(prefix, number, turn, a))
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if legal.Call > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			button("Фолд", engine.Action{Kind: engine.ActionFold}),
+			button(fmt.Sprintf("Колл %d", legal.Call), engine.Action{Kind: engine.ActionCall}),
+		))
+	} else {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button("Чек", engine.Action{Kind: engine.ActionCheck})))
+	}
+
+	var sizes []tgbotapi.InlineKeyboardButton
+	for _, a := range betSizes(h) {
+		label := fmt.Sprintf("Рейз до %d", a.Amount)
+		switch {
+		case a.Amount == legal.MaxTo:
+			label = fmt.Sprintf("Олл-ин %d", a.Amount)
+		case a.Kind == engine.ActionBet:
+			label = fmt.Sprintf("Бет %d", a.Amount)
+		}
+		sizes = append(sizes, button(label, a))
+	}
+	if len(sizes) > 0 {
+		rows = append(rows, sizes)
+	}
+	return rows
+}
+
+// betSizes offers the minimum, half the pot, the pot and all-in, counting
+// the pot after the call.
+func betSizes(h *engine.Hand) []engine.Action {
+	legal := h.Legal()
+	if !legal.CanRaise {
+		return nil
+	}
+	bet := h.Player(h.ToAct()).Bet + legal.Call
+	kind := engine.ActionBet
+	if bet > 0 {
+		kind = engine.ActionRaise
+	}
+	pot := h.Pot() + legal.Call
+
+	var targets []int
+	for _, to := range []int{legal.MinTo, bet + pot/2, bet + pot, legal.MaxTo} {
+		to = min(max(to, legal.MinTo), legal.MaxTo)
+		if !slices.Contains(targets, to) {
+			targets = append(targets, to)
+		}
+	}
+	slices.Sort(targets)
+	actions := make([]engine.Action, len(targets))
+	for i, to := range targets {
+		actions[i] = engine.Action{Kind: kind, Amount: to}
+	}
+	return actions
+}
+
+// actionCallback encodes an action button of a heads-up match or a group
+// table; prefix tells the two apart.
+func actionCallback(prefix string, number, turn int, a engine.Action) string {
+	return fmt.Sprintf("%s:%d:%d:%d:%d", prefix, number, turn, a.Kind, a.Amount)
+}
+
+// ParseActionCallback returns the hand number, the turn and the action
+// carried by callback data with the prefix.
+func ParseActionCallback(prefix, data string) (int, int, engine.Action, bool) {
+	value, ok := strings.CutPrefix(data, prefix+":")
+	if !ok {
+		return 0, 0, engine.Action{}, false
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 {
+		return 0, 0, engine.Action{}, false
+	}
+	var numbers [4]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, engine.Action{}, false
+		}
+		numbers[i] = n
+	}
+	return numbers[0], numbers[1], engine.Action{Kind: engine.ActionKind(numbers[2]), Amount: numbers[3]}, true
+}
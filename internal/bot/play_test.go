@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"pokerbot/internal/engine"
+)
+
+func TestParsePlayRequest(t *testing.T) {
+	req, err := ParsePlayRequest("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Stack != DefaultPlayStack || req.SmallBlind != 5 || req.BigBlind != 10 || req.Aggression != DefaultAggression {
+		t.Fatalf("unexpected defaults %+v", req)
+	}
+
+	req, err = ParsePlayRequest("стек: 2000 блайнды: 25/50 агрессия: агрессивный")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Stack != 2000 || req.SmallBlind != 25 || req.BigBlind != 50 || req.Aggression != 0.8 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	req, err = ParsePlayRequest("blinds: 20 aggression: 35%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.SmallBlind != 10 || req.BigBlind != 20 || req.Aggression != 0.35 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	for _, text := range []string{
+		"fast",
+		"stack: 15",
+		"blinds: 10/5",
+		"blinds: 0",
+		"aggression: 150",
+		"aggression: reckless",
+	} {
+		if _, err := ParsePlayRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestActionCallback(t *testing.T) {
+	data := actionCallback(CallbackPlayAction, 7, 5, engine.Action{Kind: engine.ActionRaise, Amount: 120})
+	number, turn, a, ok := ParseActionCallback(CallbackPlayAction, data)
+	if !ok || number != 7 || turn != 5 || a.Kind != engine.ActionRaise || a.Amount != 120 {
+		t.Fatalf("unexpected callback %q: %d %d %+v", data, number, turn, a)
+	}
+	if len(data) > 64 {
+		t.Fatalf("callback data exceeds the Telegram limit: %q", data)
+	}
+	if _, _, _, ok := ParseActionCallback(CallbackTableAction, data); ok {
+		t.Fatalf("expected a heads-up button not to parse as a table action")
+	}
+	for _, prefix := range []string{CallbackPlayAction, CallbackTableAction} {
+		if _, _, _, ok := ParseActionCallback(prefix, CallbackPlayNext); ok {
+			t.Fatalf("expected the next hand button not to parse as an action")
+		}
+		if _, _, _, ok := ParseActionCallback(prefix, CallbackTableNext); ok {
+			t.Fatalf("expected the next hand button not to parse as an action")
+		}
+	}
+}
+
+// playBot lets the bot act until it is the user's turn or the hand is over.
+func playBot(t *testing.T, ctx context.Context, g *HeadsUp) {
+	t.Helper()
+	for {
+		turn, ok := g.BotTurn()
+		if !ok {
+			return
+		}
+		if text := FormatHeadsUp(g); !strings.Contains(text, "Бот думает") {
+			t.Fatalf("expected the bot to be thinking, got:\n%s", text)
+		}
+		if err := g.ApplyBot(turn, turn.Decide(ctx)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestHeadsUpMatch(t *testing.T) {
+	g := NewHeadsUp(PlayRequest{Stack: 200, SmallBlind: 5, BigBlind: 10, Aggression: 0.5}, 1)
+	ctx := context.Background()
+	for hand := 0; hand < 20 && !g.Over(); hand++ {
+		if err := g.Deal(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if g.Hand.Button() != hand%2 {
+			t.Fatalf("expected the button to alternate, got seat %d in hand %d", g.Hand.Button(), hand+1)
+		}
+		playBot(t, ctx, g)
+		for !g.Hand.Done() {
+			keyboard := PlayKeyboard(g)
+			if len(keyboard.InlineKeyboard) < 2 {
+				t.Fatalf("expected action buttons, got %+v", keyboard)
+			}
+			text := FormatHeadsUp(g)
+			if !strings.Contains(text, "Ваш ход.") || !strings.Contains(text, "карты ") {
+				t.Fatalf("unexpected table:\n%s", text)
+			}
+
+			a := engine.Action{Kind: engine.ActionCheck}
+			if legal := g.Hand.Legal(); legal.Call > 0 {
+				a.Kind = engine.ActionCall
+			}
+			turn := g.Turn
+			if err := g.Act(turn, a); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := g.Act(turn, a); !errors.Is(err, ErrNotYourTurn) {
+				t.Fatalf("expected a repeated press to be rejected, got %v", err)
+			}
+			playBot(t, ctx, g)
+		}
+		if g.Stacks[PlayerSeat]+g.Stacks[BotSeat] != 400 {
+			t.Fatalf("chips were lost: %v", g.Stacks)
+		}
+		if text := FormatHeadsUp(g); !strings.Contains(text, "Стеки: вы") {
+			t.Fatalf("expected a hand summary, got:\n%s", text)
+		}
+	}
+	if err := g.Act(g.Turn, engine.Action{Kind: engine.ActionCheck}); err == nil {
+		t.Fatalf("expected an error when acting after the hand")
+	}
+	if !strings.Contains(FormatPlayQuit(g), "Игра окончена") {
+		t.Fatalf("unexpected quit text %q", FormatPlayQuit(g))
+	}
+}
+
+func TestHeadsUpBotFallsBack(t *testing.T) {
+	g := NewHeadsUp(PlayRequest{Stack: 1000, SmallBlind: 5, BigBlind: 10, Aggression: 0.5}, 1)
+	if err := g.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The user limps on the button; without time to decide the big blind
+	// checks preflop and again first on the flop.
+	if err := g.Act(g.Turn, engine.Action{Kind: engine.ActionCall}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	playBot(t, ctx, g)
+	if g.Hand.ToAct() != PlayerSeat || g.Hand.Street() != engine.Flop {
+		t.Fatalf("expected the user to act on the flop, got seat %d on %v", g.Hand.ToAct(), g.Hand.Street())
+	}
+	if actions := strings.Join(botActions(g.Hand), ", "); actions != "чек, чек" {
+		t.Fatalf("expected the bot to check twice, got %q", actions)
+	}
+}
+
+func TestHeadsUpStaleBotTurn(t *testing.T) {
+	g := NewHeadsUp(PlayRequest{Stack: 1000, SmallBlind: 5, BigBlind: 10, Aggression: 0.5}, 1)
+	if err := g.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := g.Act(g.Turn, engine.Action{Kind: engine.ActionCall}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	turn, ok := g.BotTurn()
+	if !ok {
+		t.Fatalf("expected the bot to act after the limp")
+	}
+	if err := g.ApplyBot(turn, engine.Action{Kind: engine.ActionCheck}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second decision for the same turn arrives after the first one.
+	if err := g.ApplyBot(turn, engine.Action{Kind: engine.ActionCheck}); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected a stale decision to be rejected, got %v", err)
+	}
+}
+
+func TestBetSizes(t *testing.T) {
+	h, err := engine.New(engine.Config{Stacks: []int{1000, 1000}, SmallBlind: 5, BigBlind: 10, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Facing the big blind, the pot after a call is 20.
+	sizes := betSizes(h)
+	want := []int{20, 30, 1000}
+	if len(sizes) != len(want) {
+		t.Fatalf("unexpected sizes %+v", sizes)
+	}
+	for i, a := range sizes {
+		if a.Kind != engine.ActionRaise || a.Amount != want[i] {
+			t.Fatalf("unexpected sizes %+v", sizes)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pokerbot/internal/poker"
+)
+
+// RangeRequest captures a range-vs-range query sent with the /rvr command.
+type RangeRequest struct {
+	Hero     poker.Range
+	Villains []poker.Range
+	Board    []poker.Card
+	Trials   int
+}
+
+var (
+	rangeKeyPattern = regexp.MustCompile(`(?i)(board|борд|стол|trials|симуляций)\s*:`)
+	versusPattern   = regexp.MustCompile(`(?i)\s+(vs|против)\s+`)
+)
+
+// maxRangeClasses limits how many hero hands are listed in the breakdown.
+const maxRangeClasses = 40
+
+// ParseRangeRequest parses text like "QQ+,AK vs 22+,A2s+ board: Kh 7d 2c".
+func ParseRangeRequest(text string) (RangeRequest, error) {
+	req := RangeRequest{Trials: DefaultTrials}
+
+	keys := rangeKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	rangesText := text
+	if len(keys) > 0 {
+		rangesText = text[:keys[0][0]]
+	}
+
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		switch key {
+		case "board", "борд", "стол":
+			board, err := parseCards(value)
+			if err != nil {
+				return RangeRequest{}, fmt.Errorf("board: %w", err)
+			}
+			if len(board) > 5 {
+				return RangeRequest{}, fmt.Errorf("board: expected up to 5 cards, got %d", len(board))
+			}
+			req.Board = board
+		case "trials", "симуляций":
+			num, err := parseInt(value)
+			if err != nil {
+				return RangeRequest{}, fmt.Errorf("trials: %w", err)
+			}
+			if num < 500 {
+				return RangeRequest{}, fmt.Errorf("trials: value must be >= 500 for stability")
+			}
+			req.Trials = num
+		}
+	}
+
+	parts := versusPattern.Split(strings.TrimSpace(rangesText), -1)
+	if len(parts) < 2 {
+		return RangeRequest{}, fmt.Errorf("expected ranges separated by \"vs\"")
+	}
+	if len(parts) > 9 {
+		return RangeRequest{}, fmt.Errorf("at most 8 opponent ranges are supported")
+	}
+
+	for i, part := range parts {
+		r, err := poker.ParseRange(part)
+		if err != nil {
+			return RangeRequest{}, fmt.Errorf("range %d: %w", i+1, err)
+		}
+		if i == 0 {
+			req.Hero = r
+		} else {
+			req.Villains = append(req.Villains, r)
+		}
+	}
+
+	return req, nil
+}
+
+// ToSimulationConfig converts a range request into a simulator configuration.
+func (r RangeRequest) ToSimulationConfig() poker.SimulationConfig {
+	cfg := poker.SimulationConfig{
+		HeroRange: r.Hero,
+		Board:     r.Board,
+		Trials:    r.Trials,
+	}
+	for _, v := range r.Villains {
+		cfg.Seats = append(cfg.Seats, poker.Seat{Range: v})
+	}
+	return cfg
+}
+
+// FormatRangeResult renders range-vs-range equity with a per-hand breakdown.
+func FormatRangeResult(req RangeRequest, result poker.SimulationResult) string {
+	var b strings.Builder
+	b.WriteString("Диапазон против диапазона:\n")
+	fmt.Fprintf(&b, "Эквити героя: %s\n", percentWithMargin(result.Equity, result.EquityMargin))
+	fmt.Fprintf(&b, "Победа: %s / Ничья: %s / Поражение: %s\n\n",
+		percentWithMargin(result.Win, result.WinMargin), percentWithMargin(result.Tie, result.TieMargin), percentWithMargin(result.Lose, result.LoseMargin))
+
+	fmt.Fprintf(&b, "Герой: %s (%d комбо)\n", req.Hero, req.Hero.Len())
+	for i, v := range req.Villains {
+		fmt.Fprintf(&b, "Соперник %d: %s (%d комбо)\n", i+1, v, v.Len())
+	}
+	if len(req.Board) > 0 {
+		fmt.Fprintf(&b, "Карты на столе: %s\n", CardsToText(req.Board))
+	} else {
+		b.WriteString("Карты на столе: пока нет\n")
+	}
+	if result.Method == poker.MethodExact {
+		fmt.Fprintf(&b, "Расчёт: точный перебор (%d исходов)\n", result.Samples)
+	} else {
+		fmt.Fprintf(&b, "Симуляций: %d\n", result.Samples)
+	}
+	if result.Partial {
+		b.WriteString("Расчёт остановлен досрочно, результат предварительный\n")
+	}
+
+	classes := classBreakdown(result.Combos)
+	if len(classes) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\nЭквити по рукам героя:\n")
+	for i, c := range classes {
+		if i == maxRangeClasses {
+			fmt.Fprintf(&b, "… и ещё %d\n", len(classes)-maxRangeClasses)
+			break
+		}
+		fmt.Fprintf(&b, "%s: %.2f%% (%d комбо)\n", c.name, c.equity, c.combos)
+	}
+	return b.String()
+}
+
+type classEquity struct {
+	name   string
+	equity float64
+	combos int
+}
+
+// classBreakdown merges per-combo results into starting hand classes, weighting
+// each combo by its number of samples, strongest first.
+func classBreakdown(combos []poker.ComboResult) []classEquity {
+	type acc struct {
+		weighted float64
+		samples  int
+		combos   int
+	}
+	byClass := make(map[string]*acc)
+	var order []string
+	for _, c := range combos {
+		name := c.Combo.Class()
+		a, ok := byClass[name]
+		if !ok {
+			a = &acc{}
+			byClass[name] = a
+			order = append(order, name)
+		}
+		a.weighted += c.Equity * float64(c.Samples)
+		a.samples += c.Samples
+		a.combos++
+	}
+
+	classes := make([]classEquity, 0, len(order))
+	for _, name := range order {
+		a := byClass[name]
+		classes = append(classes, classEquity{name: name, equity: a.weighted / float64(a.samples), combos: a.combos})
+	}
+	sort.SliceStable(classes, func(i, j int) bool { return classes[i].equity > classes[j].equity })
+	return classes
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+func TestParseRangeRequest(t *testing.T) {
+	req, err := ParseRangeRequest("QQ+,AK vs 22+,A2s+ board: Kh 7d 2c trials: 2000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Hero.Len() != 34 {
+		t.Fatalf("expected 34 hero combos, got %d", req.Hero.Len())
+	}
+	if len(req.Villains) != 1 || req.Villains[0].Len() != 78+48 {
+		t.Fatalf("unexpected villain ranges: %+v", req.Villains)
+	}
+	if len(req.Board) != 3 || req.Board[0] != poker.MustParseCard("Kh") {
+		t.Fatalf("unexpected board: %v", req.Board)
+	}
+	if req.Trials != 2000 {
+		t.Fatalf("expected overridden trials, got %d", req.Trials)
+	}
+
+	cfg := req.ToSimulationConfig()
+	if cfg.HeroRange.IsEmpty() || len(cfg.Seats) != 1 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestParseRangeRequestErrors(t *testing.T) {
+	for _, input := range []string{"QQ+", "QQ+ vs XX", "QQ+ vs AK board: Kh Zz", "QQ+ vs AK trials: 10"} {
+		if _, err := ParseRangeRequest(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestFormatRangeResult(t *testing.T) {
+	req := RangeRequest{
+		Hero:     poker.MustParseRange("AA, KK"),
+		Villains: []poker.Range{poker.MustParseRange("QQ")},
+		Trials:   5000,
+	}
+	aa := poker.MustParseRange("AhAs").Combos()[0]
+	kk := poker.MustParseRange("KhKs").Combos()[0]
+	res := poker.SimulationResult{
+		Win: 80, Tie: 1, Lose: 19, Equity: 80.5,
+		Combos: []poker.ComboResult{
+			{Combo: kk, Equity: 81, Samples: 100},
+			{Combo: aa, Equity: 82, Samples: 100},
+		},
+	}
+
+	text := FormatRangeResult(req, res)
+	for _, fragment := range []string{"Эквити героя: 80.50%", "Герой: AA, KK (12 комбо)", "Соперник 1: QQ (6 комбо)", "AA: 82.00% (1 комбо)"} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+	if strings.Index(text, "AA:") > strings.Index(text, "KK:") {
+		t.Fatalf("expected stronger hands first, got: %s", text)
+	}
+}
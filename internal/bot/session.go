@@ -2,6 +2,7 @@ package bot
 
 import (
 	"fmt"
+	"strings"
 
 	"pokerbot/internal/poker"
 )
@@ -15,12 +16,34 @@ const (
 	StepPlayers
 	StepBoard
 	StepTrials
+	StepRange
+	StepStyles
+	StepPot
+	StepStacks
+	StepPayouts
+	StepAllIn
+	StepEquity
+	StepDead
+)
+
+// MenuKind selects which builder a session belongs to.
+type MenuKind int
+
+const (
+	MenuEquity MenuKind = iota
+	MenuICM
 )
 
 // Session keeps track of a user's in-progress request via the menu.
 type Session struct {
+	Menu    MenuKind
 	Request Request
+	ICM     ICMRequest
 	Await   InputStep
+	// Play holds the heads-up match against the bot, nil when none is on.
+	Play *HeadsUp
+	// Table holds the game of a group chat, nil until a member joins.
+	Table *Table
 }
 
 // NewSession returns a session initialised with default values.
@@ -28,12 +51,19 @@ func NewSession() Session {
 	return Session{
 		Request: Request{
 			Players: 2,
-			Style:   poker.StyleBalanced,
-			Trials:  7000,
+			Style:   poker.StyleAny,
+			Trials:  DefaultTrials,
 		},
 	}
 }
 
+// NewICMSession returns a session for the ICM builder.
+func NewICMSession() Session {
+	sess := NewSession()
+	sess.Menu = MenuICM
+	return sess
+}
+
 // ApplyValue writes user-provided text into the session based on the awaited step.
 func (s *Session) ApplyValue(text string) error {
 	switch s.Await {
@@ -42,8 +72,14 @@ func (s *Session) ApplyValue(text string) error {
 		if err != nil {
 			return fmt.Errorf("hand: %w", err)
 		}
-		if len(hand) != 2 {
-			return fmt.Errorf("hand: ожидаются две карты")
+		if len(hand) != s.Request.Game.HoleCards() {
+			return fmt.Errorf("hand: ожидается карт: %d", s.Request.Game.HoleCards())
+		}
+		if err := checkDeck(s.Request.Game, hand); err != nil {
+			return fmt.Errorf("hand: %w", err)
+		}
+		if err := checkDistinct(hand, s.Request.Board, s.Request.Dead); err != nil {
+			return fmt.Errorf("hand: %w", err)
 		}
 		s.Request.Hand = hand
 	case StepPlayers:
@@ -55,6 +91,9 @@ func (s *Session) ApplyValue(text string) error {
 			return fmt.Errorf("players: минимум два игрока")
 		}
 		s.Request.Players = num
+		if len(s.Request.Styles) != num-1 {
+			s.Request.Styles = nil
+		}
 	case StepBoard:
 		board, err := parseCards(text)
 		if err != nil {
@@ -63,7 +102,29 @@ func (s *Session) ApplyValue(text string) error {
 		if len(board) > 5 {
 			return fmt.Errorf("board: максимум пять карт")
 		}
+		if err := checkDeck(s.Request.Game, board); err != nil {
+			return fmt.Errorf("board: %w", err)
+		}
+		if err := checkDistinct(s.Request.Hand, board, s.Request.Dead); err != nil {
+			return fmt.Errorf("board: %w", err)
+		}
 		s.Request.Board = board
+	case StepDead:
+		if normalize(text) == "-" {
+			s.Request.Dead = nil
+			break
+		}
+		dead, err := parseCards(text)
+		if err != nil {
+			return fmt.Errorf("dead: %w", err)
+		}
+		if err := checkDeck(s.Request.Game, dead); err != nil {
+			return fmt.Errorf("dead: %w", err)
+		}
+		if err := checkDistinct(s.Request.Hand, s.Request.Board, dead); err != nil {
+			return fmt.Errorf("dead: %w", err)
+		}
+		s.Request.Dead = dead
 	case StepTrials:
 		num, err := parseInt(text)
 		if err != nil {
@@ -72,7 +133,110 @@ func (s *Session) ApplyValue(text string) error {
 		if num < 500 {
 			return fmt.Errorf("trials: минимум 500")
 		}
-		s.Request.Trials = num
+		s.Request.Trials, s.Request.trialsSet = num, true
+	case StepRange:
+		if normalize(text) == "-" {
+			s.Request.Range = poker.Range{}
+			break
+		}
+		if s.Request.Game.IsOmaha() {
+			return fmt.Errorf("range: диапазоны недоступны в омахе")
+		}
+		if len(s.Request.Styles) > 0 {
+			return fmt.Errorf("range: сначала сбросьте стили по местам")
+		}
+		r, err := poker.ParseRange(text)
+		if err != nil {
+			return fmt.Errorf("range: %w", err)
+		}
+		s.Request.Range = r
+	case StepStyles:
+		if normalize(text) == "-" {
+			s.Request.Styles = nil
+			break
+		}
+		if !s.Request.Range.IsEmpty() {
+			return fmt.Errorf("styles: сначала сбросьте диапазон")
+		}
+		styles, err := parseStyles(text)
+		if err != nil {
+			return fmt.Errorf("styles: %w", err)
+		}
+		s.Request.Styles = styles
+		s.Request.Players = len(styles) + 1
+	case StepPot:
+		if normalize(text) == "-" {
+			s.Request.Pot, s.Request.Call, s.Request.Stack = 0, 0, 0
+			break
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("pot: ожидается банк, колл и, по желанию, стек")
+		}
+		amounts := make([]float64, 3)
+		for i, f := range fields {
+			amount, err := parseAmount(f)
+			if err != nil {
+				return fmt.Errorf("pot: %w", err)
+			}
+			amounts[i] = amount
+		}
+		if err := checkBet(amounts[0], amounts[1], amounts[2]); err != nil {
+			return err
+		}
+		s.Request.Pot, s.Request.Call, s.Request.Stack = amounts[0], amounts[1], amounts[2]
+	case StepStacks:
+		stacks, err := parseAmounts(text)
+		if err != nil {
+			return fmt.Errorf("stacks: %w", err)
+		}
+		if len(stacks) < 2 || len(stacks) > poker.MaxICMPlayers {
+			return fmt.Errorf("stacks: ожидается от 2 до %d игроков", poker.MaxICMPlayers)
+		}
+		s.ICM.Stacks = stacks
+		if s.ICM.Hero > len(stacks) || s.ICM.Villain > len(stacks) {
+			s.ICM.Hero, s.ICM.Villain = 0, 0
+		}
+	case StepPayouts:
+		payouts, err := parseAmounts(text)
+		if err != nil {
+			return fmt.Errorf("payouts: %w", err)
+		}
+		s.ICM.Payouts = payouts
+	case StepAllIn:
+		if normalize(text) == "-" {
+			s.ICM.Hero, s.ICM.Villain, s.ICM.Pot = 0, 0, 0
+			break
+		}
+		if len(s.ICM.Stacks) == 0 {
+			return fmt.Errorf("all-in: сначала задайте стеки")
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("all-in: ожидаются номера героя и соперника и, по желанию, банк")
+		}
+		hero, err := parseInt(fields[0])
+		if err != nil {
+			return fmt.Errorf("hero: %w", err)
+		}
+		villain, err := parseInt(fields[1])
+		if err != nil {
+			return fmt.Errorf("villain: %w", err)
+		}
+		if hero < 1 || villain < 1 || hero > len(s.ICM.Stacks) || villain > len(s.ICM.Stacks) || hero == villain {
+			return fmt.Errorf("all-in: ожидаются два разных игрока из %d", len(s.ICM.Stacks))
+		}
+		var pot float64
+		if len(fields) == 3 {
+			if pot, err = parseAmount(fields[2]); err != nil {
+				return fmt.Errorf("pot: %w", err)
+			}
+		}
+		s.ICM.Hero, s.ICM.Villain, s.ICM.Pot = hero, villain, pot
+	case StepEquity:
+		if err := s.ICM.setEquity(text); err != nil {
+			return fmt.Errorf("equity: %w", err)
+		}
 	default:
 		return fmt.Errorf("нет ожидаемого ввода")
 	}
@@ -81,7 +245,30 @@ func (s *Session) ApplyValue(text string) error {
 	return nil
 }
 
+// SetGame switches the variant, dropping the hand, board, dead cards and range
+// when they no longer fit it.
+func (s *Session) SetGame(game poker.Game) {
+	s.Request.Game = game
+	if len(s.Request.Hand) != game.HoleCards() || checkDeck(game, s.Request.Hand) != nil {
+		s.Request.Hand = nil
+	}
+	if checkDeck(game, s.Request.Board) != nil {
+		s.Request.Board = nil
+	}
+	if checkDeck(game, s.Request.Dead) != nil {
+		s.Request.Dead = nil
+	}
+	if game.IsOmaha() {
+		s.Request.Range = poker.Range{}
+	}
+}
+
 // HasRequiredFields reports whether the menu request is ready for simulation.
 func (s Session) HasRequiredFields() bool {
-	return len(s.Request.Hand) == 2 && s.Request.Players >= 2
+	return len(s.Request.Hand) == s.Request.Game.HoleCards() && s.Request.Players >= 2
+}
+
+// ICMReady reports why the ICM builder cannot be calculated yet, or nil.
+func (s Session) ICMReady() error {
+	return s.ICM.check()
 }
@@ -1,6 +1,11 @@
 package bot
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
 
 func TestSessionApplyValue(t *testing.T) {
 	sess := NewSession()
@@ -47,3 +52,213 @@ func TestSessionApplyValueErrors(t *testing.T) {
 		t.Fatal("expected error for players")
 	}
 }
+
+func TestSessionApplyRange(t *testing.T) {
+	sess := NewSession()
+	sess.Await = StepRange
+	if err := sess.ApplyValue("JJ+, AQs+"); err != nil {
+		t.Fatalf("unexpected range error: %v", err)
+	}
+	if sess.Request.Range.IsEmpty() {
+		t.Fatal("range not stored")
+	}
+
+	sess.Await = StepRange
+	if err := sess.ApplyValue("-"); err != nil {
+		t.Fatalf("unexpected reset error: %v", err)
+	}
+	if !sess.Request.Range.IsEmpty() {
+		t.Fatal("expected range to be cleared")
+	}
+
+	sess.Await = StepRange
+	if err := sess.ApplyValue("ZZ"); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+}
+
+func TestSessionApplyStyles(t *testing.T) {
+	sess := NewSession()
+	sess.Await = StepStyles
+	if err := sess.ApplyValue("тайт, луз, loose"); err != nil {
+		t.Fatalf("unexpected styles error: %v", err)
+	}
+	if len(sess.Request.Styles) != 3 || sess.Request.Players != 4 {
+		t.Fatalf("expected three seats and four players, got %+v", sess.Request)
+	}
+
+	sess.Await = StepPlayers
+	if err := sess.ApplyValue("6"); err != nil {
+		t.Fatalf("unexpected players error: %v", err)
+	}
+	if sess.Request.Styles != nil {
+		t.Fatal("expected seat styles to reset when the player count changes")
+	}
+
+	sess.Await = StepRange
+	if err := sess.ApplyValue("QQ+"); err != nil {
+		t.Fatalf("unexpected range error: %v", err)
+	}
+	sess.Await = StepStyles
+	if err := sess.ApplyValue("tight, loose"); err == nil {
+		t.Fatal("expected error for seat styles alongside a range")
+	}
+}
+
+func TestSessionSetGame(t *testing.T) {
+	sess := NewSession()
+	sess.Await = StepHand
+	if err := sess.ApplyValue("Ah Kh"); err != nil {
+		t.Fatalf("unexpected hand error: %v", err)
+	}
+
+	sess.SetGame(poker.GameOmaha4)
+	if sess.Request.Hand != nil || sess.HasRequiredFields() {
+		t.Fatal("expected hold'em hand to be dropped when switching to Omaha")
+	}
+
+	sess.Await = StepHand
+	if err := sess.ApplyValue("Ah Kh Qd Jd"); err != nil {
+		t.Fatalf("unexpected Omaha hand error: %v", err)
+	}
+	if !sess.HasRequiredFields() {
+		t.Fatal("expected Omaha session to be ready")
+	}
+
+	sess.Await = StepRange
+	if err := sess.ApplyValue("QQ+"); err == nil {
+		t.Fatal("expected error for ranges in Omaha")
+	}
+}
+
+func TestSessionSetGameShortDeck(t *testing.T) {
+	sess := NewSession()
+	sess.Request.Hand = []poker.Card{poker.MustParseCard("Ah"), poker.MustParseCard("5h")}
+	sess.Request.Board = []poker.Card{poker.MustParseCard("Kc"), poker.MustParseCard("7d"), poker.MustParseCard("8s")}
+
+	sess.SetGame(poker.GameShortDeck)
+	if sess.Request.Hand != nil {
+		t.Fatal("expected a hand with a five to be dropped in short deck")
+	}
+	if len(sess.Request.Board) != 3 {
+		t.Fatal("expected the board to be kept")
+	}
+
+	sess.Await = StepHand
+	if err := sess.ApplyValue("Ah 4h"); err == nil {
+		t.Fatal("expected error for a four in short deck")
+	}
+}
+
+func TestSessionApplyPot(t *testing.T) {
+	sess := NewSession()
+	sess.Await = StepPot
+	if err := sess.ApplyValue("120 40 900"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sess.Request.Pot != 120 || sess.Request.Call != 40 || sess.Request.Stack != 900 {
+		t.Fatalf("unexpected request %+v", sess.Request)
+	}
+	if !strings.Contains(SessionSummary(sess), "120 / 40 / 900") {
+		t.Fatalf("expected the bet in the summary, got: %s", SessionSummary(sess))
+	}
+
+	sess.Await = StepPot
+	if err := sess.ApplyValue("40"); err == nil {
+		t.Fatal("expected error without the call")
+	}
+	sess.Await = StepPot
+	if err := sess.ApplyValue("-"); err != nil || sess.Request.Call != 0 {
+		t.Fatalf("expected the bet to be cleared, got %+v (%v)", sess.Request, err)
+	}
+}
+
+func TestSessionICM(t *testing.T) {
+	sess := NewICMSession()
+	if sess.ICMReady() == nil {
+		t.Fatal("expected an empty ICM session not to be ready")
+	}
+
+	sess.Await = StepAllIn
+	if err := sess.ApplyValue("1 2"); err == nil {
+		t.Fatal("expected error before the stacks are set")
+	}
+
+	steps := []struct {
+		step InputStep
+		text string
+	}{
+		{StepStacks, "1500 2300 800"},
+		{StepPayouts, "50 30 20"},
+		{StepAllIn, "3 2 150"},
+		{StepEquity, "Ah 9h vs 22+, A2s+"},
+	}
+	for _, s := range steps {
+		sess.Await = s.step
+		if err := sess.ApplyValue(s.text); err != nil {
+			t.Fatalf("unexpected error for %q: %v", s.text, err)
+		}
+	}
+	if err := sess.ICMReady(); err != nil {
+		t.Fatalf("expected the session to be ready, got %v", err)
+	}
+	if sess.ICM.Hero != 3 || sess.ICM.Pot != 150 || !sess.ICM.NeedsEquity() {
+		t.Fatalf("unexpected ICM request %+v", sess.ICM)
+	}
+	summary := ICMSummary(sess)
+	for _, fragment := range []string{"Стеки: 1500 / 2300 / 800", "игрок 3 против игрока 2, банк 150", "Ah 9h против 22+, A2s+"} {
+		if !strings.Contains(summary, fragment) {
+			t.Fatalf("expected summary to contain %q, got: %s", fragment, summary)
+		}
+	}
+
+	sess.Await = StepStacks
+	if err := sess.ApplyValue("1500 2300"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sess.ICM.HasDecision() {
+		t.Fatal("expected the all-in to reset when its player leaves the table")
+	}
+
+	sess.Await = StepEquity
+	if err := sess.ApplyValue("45%"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sess.Await = StepEquity
+	if err := sess.ApplyValue("Ah Kh Qh vs QQ+"); err == nil {
+		t.Fatal("expected error for three hole cards")
+	}
+	if sess.ICM.NeedsEquity() || sess.ICM.Equity != 45 || !sess.ICM.Range.IsEmpty() {
+		t.Fatalf("expected a rejected hand to keep the equity, got %+v", sess.ICM)
+	}
+}
+
+func TestSessionApplyDead(t *testing.T) {
+	sess := NewSession()
+	sess.Await = StepHand
+	if err := sess.ApplyValue("Ah Kh"); err != nil {
+		t.Fatalf("unexpected hand error: %v", err)
+	}
+
+	sess.Await = StepDead
+	if err := sess.ApplyValue("Ah 2d"); err == nil {
+		t.Fatal("expected error for a dead card in the hero's hand")
+	}
+	sess.Await = StepDead
+	if err := sess.ApplyValue("7c 2d"); err != nil {
+		t.Fatalf("unexpected dead error: %v", err)
+	}
+	if !strings.Contains(SessionSummary(sess), "Мёртвые карты: 7c 2d") {
+		t.Fatalf("expected dead cards in the summary, got: %s", SessionSummary(sess))
+	}
+
+	sess.Await = StepBoard
+	if err := sess.ApplyValue("Qh Jh 7c"); err == nil {
+		t.Fatal("expected error for a board card that is dead")
+	}
+
+	sess.SetGame(poker.GameShortDeck)
+	if sess.Request.Dead != nil {
+		t.Fatal("expected dead cards with a deuce to be dropped in short deck")
+	}
+}
@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"pokerbot/internal/poker"
+)
+
+// ShowdownRequest captures a finished hand sent with the /showdown command.
+type ShowdownRequest struct {
+	Game  poker.Game
+	Board []poker.Card
+	// Players are numbered from 1 in seat order, starting left of the button.
+	Players []poker.ShowdownPlayer
+}
+
+var showdownKeyPattern = regexp.MustCompile(`(?i)(p\d+|игрок\s*\d+|board|борд|стол|stacks|стеки|ставки|game|игра)\s*:`)
+
+var seatNumberPattern = regexp.MustCompile(`\d+`)
+
+// foldWords mark a player who folded instead of showing cards.
+var foldWords = map[string]bool{"fold": true, "фолд": true, "пас": true, "-": true}
+
+// ParseShowdownRequest parses text like
+// "p1: Ah Kd p2: Qs Qc board: 2c 7d 9h Ts 3s stacks: 100 250".
+func ParseShowdownRequest(text string) (ShowdownRequest, error) {
+	var req ShowdownRequest
+
+	keys := showdownKeyPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(keys) == 0 || strings.TrimSpace(text[:keys[0][0]]) != "" {
+		return ShowdownRequest{}, fmt.Errorf("expected key: value pairs such as p1:, board: and stacks:")
+	}
+
+	seats := make(map[int]poker.ShowdownPlayer)
+	var stacks []int
+	for i, loc := range keys {
+		end := len(text)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		key := normalize(text[loc[2]:loc[3]])
+		value := strings.TrimSpace(text[loc[1]:end])
+
+		var err error
+		switch key {
+		case "board", "борд", "стол":
+			req.Board, err = parseCards(value)
+		case "stacks", "стеки", "ставки":
+			stacks, err = parseChips(value)
+		case "game", "игра":
+			game, ok := gameAliases[normalize(value)]
+			if !ok {
+				err = fmt.Errorf("unknown game: %s", value)
+			}
+			req.Game = game
+		default:
+			seat, _ := strconv.Atoi(seatNumberPattern.FindString(key))
+			if _, dup := seats[seat]; dup || seat < 1 {
+				err = fmt.Errorf("expected each player number once, from 1")
+				break
+			}
+			var p poker.ShowdownPlayer
+			if foldWords[normalize(value)] {
+				p.Folded = true
+			} else if p.Hole, err = parseCards(value); err == nil && len(p.Hole) == 0 {
+				err = fmt.Errorf("specify the player's cards or fold")
+			}
+			seats[seat] = p
+		}
+		if err != nil {
+			return ShowdownRequest{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	for seat := 1; seat <= len(seats); seat++ {
+		p, ok := seats[seat]
+		if !ok {
+			return ShowdownRequest{}, fmt.Errorf("player %d is missing", seat)
+		}
+		req.Players = append(req.Players, p)
+	}
+	if len(req.Players) < 2 {
+		return ShowdownRequest{}, fmt.Errorf("specify at least two players as p1:, p2:")
+	}
+	if len(req.Board) != 5 {
+		return ShowdownRequest{}, fmt.Errorf("board: expected 5 cards, got %d", len(req.Board))
+	}
+	if len(stacks) != len(req.Players) {
+		return ShowdownRequest{}, fmt.Errorf("stacks: expected the chips each of the %d players put in", len(req.Players))
+	}
+	for i := range req.Players {
+		req.Players[i].Contributed = stacks[i]
+	}
+	return req, nil
+}
+
+// ToShowdownConfig converts the request into the resolver configuration.
+func (r ShowdownRequest) ToShowdownConfig() poker.ShowdownConfig {
+	return poker.ShowdownConfig{Game: r.Game, Board: r.Board, Players: r.Players}
+}
+
+// FormatShowdownResult lists every player's hand and who wins each pot.
+func FormatShowdownResult(req ShowdownRequest, result poker.ShowdownResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Борд: %s\n\n", CardsToText(req.Board))
+	for i, p := range req.Players {
+		if p.Folded {
+			fmt.Fprintf(&b, "Игрок %d: фолд\n", i+1)
+			continue
+		}
+		fmt.Fprintf(&b, "Игрок %d: %s — %s\n", i+1, CardsToText(p.Hole), HandDisplay(result.Hands[i]))
+	}
+
+	b.WriteString("\n")
+	for i, pot := range result.Pots {
+		name := "Основной банк"
+		if i > 0 {
+			name = fmt.Sprintf("Побочный банк %d", i)
+		}
+		switch {
+		case len(pot.Eligible) == 1:
+			fmt.Fprintf(&b, "%s: %d — достаётся игроку %d, остальные не уравняли или сбросили\n", name, pot.Amount, pot.Eligible[0]+1)
+		case len(pot.Winners) == 1:
+			fmt.Fprintf(&b, "%s: %d (игроки %s) — выигрывает игрок %d\n", name, pot.Amount, seatsDisplay(pot.Eligible), pot.Winners[0]+1)
+		default:
+			fmt.Fprintf(&b, "%s: %d (игроки %s) — делят игроки %s\n", name, pot.Amount, seatsDisplay(pot.Eligible), seatsDisplay(pot.Winners))
+		}
+	}
+
+	b.WriteString("\nИтог:\n")
+	for i, p := range req.Players {
+		won := result.Winnings[i]
+		fmt.Fprintf(&b, "Игрок %d: вложил %d, получает %d (%+d)\n", i+1, p.Contributed, won, won-p.Contributed)
+	}
+	return b.String()
+}
+
+func seatsDisplay(seats []int) string {
+	parts := make([]string, len(seats))
+	for i, s := range seats {
+		parts[i] = strconv.Itoa(s + 1)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseChips reads whole chip amounts; pots are split down to single chips.
+func parseChips(value string) ([]int, error) {
+	amounts, err := parseAmounts(value)
+	if err != nil {
+		return nil, err
+	}
+	chips := make([]int, len(amounts))
+	for i, a := range amounts {
+		if a != math.Trunc(a) {
+			return nil, fmt.Errorf("expected whole chips, got %s", formatAmount(a))
+		}
+		chips[i] = int(a)
+	}
+	return chips, nil
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+func TestParseShowdownRequest(t *testing.T) {
+	req, err := ParseShowdownRequest("p1: Ah Ad p2: Kh Kd p3: fold board: 2c 7d 9h Ts 3s stacks: 100 250 40")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Players) != 3 || !req.Players[2].Folded || req.Players[1].Contributed != 250 || len(req.Board) != 5 {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	req, err = ParseShowdownRequest("игрок 2: Kh Kd игрок 1: Ah Ad борд: 2c 7d 9h Ts 3s ставки: 100, 250")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Players[0].Hole[0] != poker.MustParseCard("Ah") || req.Players[1].Contributed != 250 {
+		t.Fatalf("expected players in seat order, got %+v", req.Players)
+	}
+
+	for _, text := range []string{
+		"",
+		"p1: Ah Ad board: 2c 7d 9h Ts 3s stacks: 100",
+		"p1: Ah Ad p3: Kh Kd board: 2c 7d 9h Ts 3s stacks: 100 100",
+		"p1: Ah Ad p1: Kh Kd board: 2c 7d 9h Ts 3s stacks: 100 100",
+		"p1: Ah Ad p2: Kh Kd board: 2c 7d 9h stacks: 100 100",
+		"p1: Ah Ad p2: Kh Kd board: 2c 7d 9h Ts 3s stacks: 100",
+		"p1: Ah Ad p2: Kh Kd board: 2c 7d 9h Ts 3s stacks: 100 12.5",
+		"p1: Ah Ad p2: board: 2c 7d 9h Ts 3s stacks: 100 100",
+	} {
+		if _, err := ParseShowdownRequest(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestFormatShowdownResult(t *testing.T) {
+	req, err := ParseShowdownRequest("p1: Ah Ad p2: Kh Kd p3: Qh Qd board: 2c 7d 9h Ts 3s stacks: 100 250 400")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := poker.ResolveShowdown(req.ToShowdownConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := FormatShowdownResult(req, result)
+	for _, fragment := range []string{
+		"Игрок 1: Ah Ad — Пара тузов, кикер десятка",
+		"Основной банк: 300 (игроки 1, 2, 3) — выигрывает игрок 1",
+		"Побочный банк 1: 300 (игроки 2, 3) — выигрывает игрок 2",
+		"Побочный банк 2: 150 — достаётся игроку 3",
+		"Игрок 3: вложил 400, получает 150 (-250)",
+	} {
+		if !strings.Contains(text, fragment) {
+			t.Fatalf("expected output to contain %q, got: %s", fragment, text)
+		}
+	}
+}
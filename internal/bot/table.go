@@ -0,0 +1,340 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"pokerbot/internal/engine"
+)
+
+// MaxTableSeats caps the members playing at a group table.
+const MaxTableSeats = 9
+
+const (
+	// CallbackTableAction carries a member's action at a group table.
+	CallbackTableAction = "table_act"
+	CallbackTableNext   = "table_next"
+)
+
+// TablePlayer is a member seated at a group table.
+type TablePlayer struct {
+	UserID int64
+	Name   string
+	Stack  int
+	// Leaving players fold when their turn comes and give up the seat
+	// before the next hand.
+	Leaving bool
+}
+
+// Table is the game of a group chat: members join and play hands with the
+// bot dealing. Players are seated in the order they joined; seat numbers of
+// the hand are indexes into Players, and members who join during a hand wait
+// for the next one.
+type Table struct {
+	Request PlayRequest
+	Players []TablePlayer
+	// Button indexes Players, -1 before the first hand.
+	Button int
+	Number int
+	Hand   *engine.Hand
+	// Turn counts the actions of the hand, so a stale button press or turn
+	// timeout is ignored.
+	Turn int
+	// MessageID is the group message showing the hand.
+	MessageID int
+	rng       *rand.Rand
+}
+
+// NewTable opens a table with the stack and blinds of req; the seed drives
+// the deals.
+func NewTable(req PlayRequest, seed int64) *Table {
+	return &Table{Request: req, Button: -1, rng: rand.New(rand.NewSource(seed))}
+}
+
+// InHand reports whether a hand is being played.
+func (t *Table) InHand() bool {
+	return t.Hand != nil && !t.Hand.Done()
+}
+
+// Seated reports whether the member has a seat they are not leaving.
+func (t *Table) Seated(userID int64) bool {
+	for _, p := range t.Players {
+		if p.UserID == userID {
+			return !p.Leaving
+		}
+	}
+	return false
+}
+
+// CanJoin returns the error Join would report for the member, so a seat can
+// be checked before anything is sent to them.
+func (t *Table) CanJoin(userID int64) error {
+	for _, p := range t.Players {
+		if p.UserID == userID {
+			if !p.Leaving {
+				return errors.New("you are already at the table")
+			}
+			return nil
+		}
+	}
+	if len(t.Players) >= MaxTableSeats {
+		return fmt.Errorf("the table is full: %d players", MaxTableSeats)
+	}
+	return nil
+}
+
+// Join seats a member with the table's starting stack; a member who is
+// leaving keeps their seat instead.
+func (t *Table) Join(userID int64, name string) error {
+	if err := t.CanJoin(userID); err != nil {
+		return err
+	}
+	for i, p := range t.Players {
+		if p.UserID == userID {
+			t.Players[i].Leaving = false
+			return nil
+		}
+	}
+	t.Players = append(t.Players, TablePlayer{UserID: userID, Name: name, Stack: t.Request.Stack})
+	return nil
+}
+
+// Leave frees the member's seat, folding their hand when it is their turn;
+// during a hand the seat is kept until the hand ends.
+func (t *Table) Leave(userID int64) error {
+	for i, p := range t.Players {
+		if p.UserID != userID {
+			continue
+		}
+		t.Players[i].Leaving = true
+		if t.InHand() {
+			t.foldLeaving()
+		} else {
+			t.prune()
+		}
+		return nil
+	}
+	return errors.New("you are not at the table")
+}
+
+// Deal starts the next hand with the button moved one seat, after members
+// who left or lost every chip give up their seats.
+func (t *Table) Deal() error {
+	if t.InHand() {
+		return errors.New("the current hand is not over")
+	}
+	t.prune()
+	if len(t.Players) < 2 {
+		return errors.New("at least two players must join the table")
+	}
+	button := (t.Button + 1) % len(t.Players)
+	stacks := make([]int, len(t.Players))
+	for i, p := range t.Players {
+		stacks[i] = p.Stack
+	}
+	hand, err := engine.New(engine.Config{
+		Stacks:     stacks,
+		Button:     button,
+		SmallBlind: t.Request.SmallBlind,
+		BigBlind:   t.Request.BigBlind,
+		Seed:       t.rng.Int63(),
+	})
+	if err != nil {
+		return err
+	}
+	t.Hand, t.Button, t.Turn = hand, button, 0
+	t.Number++
+	return nil
+}
+
+// ToAct returns the member to act, or nil when no hand awaits an action.
+func (t *Table) ToAct() *TablePlayer {
+	if !t.InHand() {
+		return nil
+	}
+	return &t.Players[t.Hand.ToAct()]
+}
+
+// Act applies a member's action; turn must match the table's turn counter.
+func (t *Table) Act(userID int64, turn int, a engine.Action) error {
+	p := t.ToAct()
+	if p == nil || p.UserID != userID || turn != t.Turn {
+		return ErrNotYourTurn
+	}
+	if err := t.Hand.Apply(a); err != nil {
+		return err
+	}
+	t.advance()
+	return nil
+}
+
+// Timeout checks or folds for the member whose time ran out and returns the
+// action taken. It reports false when the turn has already moved on.
+func (t *Table) Timeout(number, turn int) (engine.Action, bool) {
+	if !t.InHand() || number != t.Number || turn != t.Turn {
+		return engine.Action{}, false
+	}
+	a := engine.Action{Kind: engine.ActionFold}
+	if t.Hand.Legal().Call == 0 {
+		a.Kind = engine.ActionCheck
+	}
+	if err := t.Hand.Apply(a); err != nil {
+		return engine.Action{}, false
+	}
+	t.advance()
+	return a, true
+}
+
+func (t *Table) advance() {
+	t.Turn++
+	t.foldLeaving()
+}
+
+// foldLeaving folds for members who left while it is their turn and settles
+// the stacks once the hand ends.
+func (t *Table) foldLeaving() {
+	for t.InHand() && t.Players[t.Hand.ToAct()].Leaving {
+		if err := t.Hand.Apply(engine.Action{Kind: engine.ActionFold}); err != nil {
+			break
+		}
+		t.Turn++
+	}
+	if t.Hand != nil && t.Hand.Done() {
+		for i, stack := range t.Hand.Stacks() {
+			t.Players[i].Stack = stack
+		}
+	}
+}
+
+// prune removes members who left or lost every chip, keeping the button on
+// the seat before the next button.
+func (t *Table) prune() {
+	var kept []TablePlayer
+	button := t.Button
+	for i, p := range t.Players {
+		if p.Leaving || p.Stack == 0 {
+			if i <= t.Button {
+				button--
+			}
+			continue
+		}
+		kept = append(kept, p)
+	}
+	t.Players, t.Button = kept, button
+}
+
+// FormatTableLobby lists the members waiting for a hand.
+func FormatTableLobby(t *Table) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Стол: стек %d, блайнды %d/%d\n\n", t.Request.Stack, t.Request.SmallBlind, t.Request.BigBlind)
+	for i, p := range t.Players {
+		fmt.Fprintf(&b, "%d. %s — %d\n", i+1, p.Name, p.Stack)
+	}
+	if len(t.Players) < 2 {
+		b.WriteString("\nЖдём ещё игроков: /join")
+	} else {
+		b.WriteString("\nСесть за стол: /join, начать раздачу: /deal")
+	}
+	return b.String()
+}
+
+// FormatHoleCards is the private message with a member's cards.
+func FormatHoleCards(t *Table, seat int, title string) string {
+	return fmt.Sprintf("Раздача #%d в «%s»: ваши карты %s", t.Number, title, CardsToText(t.Hand.Player(seat).Hole))
+}
+
+// FormatTable shows the hand to the group: board, pot, every seat and whose
+// turn it is with the time to act. A finished hand ends with the showdown and
+// the pots.
+func FormatTable(t *Table, turnTime time.Duration) string {
+	h := t.Hand
+	var b strings.Builder
+	fmt.Fprintf(&b, "Раздача #%d, блайнды %d/%d\n", t.Number, t.Request.SmallBlind, t.Request.BigBlind)
+	if board := h.Board(); len(board) > 0 {
+		fmt.Fprintf(&b, "Борд: %s\n", CardsToText(board))
+	}
+	fmt.Fprintf(&b, "Банк: %d\n\n", h.Pot())
+
+	for seat := range h.Seats() {
+		p := h.Player(seat)
+		fmt.Fprintf(&b, "%d. %s — %d", seat+1, t.Players[seat].Name, p.Stack)
+		switch {
+		case p.Folded:
+			b.WriteString(", фолд")
+		case p.AllIn() && !h.Done():
+			b.WriteString(", олл-ин")
+		case p.Bet > 0 && !h.Done():
+			fmt.Fprintf(&b, ", ставка %d", p.Bet)
+		}
+		if seat == h.Button() {
+			b.WriteString(" (баттон)")
+		}
+		b.WriteString("\n")
+	}
+
+	log := h.Log()
+	if last := log[len(log)-1]; last.Kind != engine.ActionPost {
+		fmt.Fprintf(&b, "\nПоследний ход: %s — %s\n", t.Players[last.Seat].Name, eventDisplay(last))
+	}
+	if p := t.ToAct(); p != nil {
+		legal := h.Legal()
+		move := "можно чек"
+		if legal.Call > 0 {
+			move = fmt.Sprintf("колл %d", legal.Call)
+		}
+		fmt.Fprintf(&b, "\nХод: %s — %s, на решение %d с", p.Name, move, int(turnTime.Seconds()))
+		return b.String()
+	}
+
+	res := h.Result()
+	b.WriteString("\n")
+	if res.Showdown {
+		for seat := range h.Seats() {
+			if p := h.Player(seat); p.Live() {
+				fmt.Fprintf(&b, "%s: %s — %s\n", t.Players[seat].Name, CardsToText(p.Hole), HandDisplay(res.Hands[seat]))
+			}
+		}
+		b.WriteString("\n")
+	}
+	for i, pot := range res.Pots {
+		name := "Основной банк"
+		if i > 0 {
+			name = fmt.Sprintf("Побочный банк %d", i)
+		}
+		names := make([]string, len(pot.Winners))
+		for j, w := range pot.Winners {
+			names[j] = t.Players[w].Name
+		}
+		verb := "забирает"
+		if len(names) > 1 {
+			verb = "делят"
+		}
+		fmt.Fprintf(&b, "%s: %d — %s %s\n", name, pot.Amount, verb, strings.Join(names, ", "))
+	}
+
+	b.WriteString("\nСтеки:")
+	for seat := range h.Seats() {
+		p := t.Players[seat]
+		fmt.Fprintf(&b, "\n%s — %d", p.Name, p.Stack)
+		if p.Stack == 0 {
+			b.WriteString(", выбывает")
+		}
+	}
+	return b.String()
+}
+
+// TableKeyboard offers the actions of the member to act, or the next hand
+// once the hand is over.
+func TableKeyboard(t *Table) tgbotapi.InlineKeyboardMarkup {
+	if !t.InHand() {
+		return tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Следующая раздача", CallbackTableNext)),
+		)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(actionRows(t.Hand, CallbackTableAction, t.Number, t.Turn)...)
+}
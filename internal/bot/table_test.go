@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pokerbot/internal/engine"
+)
+
+func newTestTable(t *testing.T, players ...string) *Table {
+	t.Helper()
+	table := NewTable(PlayRequest{Stack: 500, SmallBlind: 5, BigBlind: 10}, 1)
+	for i, name := range players {
+		if err := table.Join(int64(i+1), name); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return table
+}
+
+// callDown checks or calls for every player until the hand ends.
+func callDown(t *testing.T, table *Table) {
+	t.Helper()
+	for table.InHand() {
+		a := engine.Action{Kind: engine.ActionCheck}
+		if table.Hand.Legal().Call > 0 {
+			a.Kind = engine.ActionCall
+		}
+		if err := table.Act(table.ToAct().UserID, table.Turn, a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestTableJoin(t *testing.T) {
+	table := newTestTable(t, "Аня")
+	if err := table.Deal(); err == nil {
+		t.Fatalf("expected an error dealing to a single player")
+	}
+	if err := table.CanJoin(1); err == nil {
+		t.Fatalf("expected a seated member not to be able to join")
+	}
+	if !table.Seated(1) || table.Seated(2) {
+		t.Fatalf("expected only the first member to be seated")
+	}
+	if err := table.Join(1, "Аня"); err == nil {
+		t.Fatalf("expected an error joining twice")
+	}
+	if err := table.CanJoin(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 2; i <= MaxTableSeats; i++ {
+		if err := table.Join(int64(i), "Игрок"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if table.CanJoin(100) == nil || table.Join(100, "Лишний") == nil {
+		t.Fatalf("expected an error joining a full table")
+	}
+	if err := table.Leave(100); err == nil {
+		t.Fatalf("expected an error leaving without a seat")
+	}
+	if err := table.Leave(2); err != nil || len(table.Players) != MaxTableSeats-1 {
+		t.Fatalf("expected the seat to free between hands, got %v and %d players", err, len(table.Players))
+	}
+	if text := FormatTableLobby(table); !strings.Contains(text, "1. Аня — 500") || !strings.Contains(text, "/deal") {
+		t.Fatalf("unexpected lobby:\n%s", text)
+	}
+}
+
+func TestTableHand(t *testing.T) {
+	table := newTestTable(t, "Аня", "Борис", "Вика")
+	if err := table.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table.Button != 0 || table.ToAct().Name != "Аня" {
+		t.Fatalf("expected the button to act first three-handed, got button %d", table.Button)
+	}
+	if text := FormatHoleCards(table, 1, "Клуб"); !strings.HasPrefix(text, "Раздача #1 в «Клуб»: ваши карты ") {
+		t.Fatalf("unexpected hole cards message %q", text)
+	}
+	if text := FormatTable(table, time.Minute); !strings.Contains(text, "Ход: Аня — колл 10, на решение 60 с") {
+		t.Fatalf("unexpected table:\n%s", text)
+	}
+
+	call := engine.Action{Kind: engine.ActionCall}
+	if err := table.Act(2, table.Turn, call); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected an out of turn error, got %v", err)
+	}
+	if err := table.Act(1, table.Turn+1, call); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected a stale button to be rejected, got %v", err)
+	}
+	if err := table.Act(1, table.Turn, call); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A member joining mid-hand waits for the next one.
+	if err := table.Join(4, "Гоша"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := table.Timeout(table.Number, table.Turn-1); ok {
+		t.Fatalf("expected a stale timeout to be ignored")
+	}
+	if a, ok := table.Timeout(table.Number, table.Turn); !ok || a.Kind != engine.ActionFold || !table.Hand.Player(1).Folded {
+		t.Fatalf("expected the small blind to fold on timeout, got %+v", a)
+	}
+	callDown(t, table)
+
+	total := 0
+	for _, p := range table.Players {
+		total += p.Stack
+	}
+	if total != 2000 || table.Players[1].Stack != 495 {
+		t.Fatalf("unexpected stacks %+v", table.Players)
+	}
+	text := FormatTable(table, time.Minute)
+	if !strings.Contains(text, "Основной банк: 25") || !strings.Contains(text, "Борис — 495") || strings.Contains(text, "Гоша") {
+		t.Fatalf("unexpected summary:\n%s", text)
+	}
+	if keyboard := TableKeyboard(table); keyboard.InlineKeyboard[0][0].Text != "Следующая раздача" {
+		t.Fatalf("expected the next hand button, got %+v", keyboard)
+	}
+
+	if err := table.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table.Button != 1 || table.Hand.Seats() != 4 {
+		t.Fatalf("expected the button to move and the new member to play, got button %d, %d seats", table.Button, table.Hand.Seats())
+	}
+}
+
+func TestTableLeave(t *testing.T) {
+	table := newTestTable(t, "Аня", "Борис", "Вика")
+	if err := table.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Leaving on one's turn folds the hand at once; leaving out of turn
+	// folds it when the turn comes.
+	if err := table.Leave(3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table.Turn != 0 {
+		t.Fatalf("expected no fold before the big blind's turn")
+	}
+	if err := table.Leave(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !table.Hand.Player(0).Folded || table.ToAct().Name != "Борис" {
+		t.Fatalf("expected the button to fold and the small blind to act")
+	}
+	if err := table.Act(2, table.Turn, engine.Action{Kind: engine.ActionCall}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table.InHand() || !table.Hand.Player(2).Folded {
+		t.Fatalf("expected the big blind to fold on their turn and end the hand")
+	}
+	if table.Players[1].Stack != 510 {
+		t.Fatalf("expected the small blind to win the blinds, got %+v", table.Players)
+	}
+
+	if err := table.Join(5, "Дима"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := table.Deal(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The button passes from the departed button to the next seat.
+	if len(table.Players) != 2 || table.Players[0].Name != "Борис" || table.Button != 0 {
+		t.Fatalf("expected the leavers to give up their seats, got %+v with button %d", table.Players, table.Button)
+	}
+}
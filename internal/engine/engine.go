@@ -0,0 +1,563 @@
+// Package engine plays out No-Limit Hold'em hands: blinds, betting rounds,
+// all-ins and the showdown.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"pokerbot/internal/poker"
+)
+
+// MaxSeats is the most players a single deck can deal hole cards and a board to.
+const MaxSeats = 23
+
+// Street is a betting round; Showdown marks a hand whose cards were turned over.
+type Street int
+
+const (
+	Preflop Street = iota
+	Flop
+	Turn
+	River
+	Showdown
+)
+
+func (s Street) String() string {
+	switch s {
+	case Flop:
+		return "flop"
+	case Turn:
+		return "turn"
+	case River:
+		return "river"
+	case Showdown:
+		return "showdown"
+	default:
+		return "preflop"
+	}
+}
+
+// ActionKind is what a player does.
+type ActionKind int
+
+const (
+	// ActionPost is a forced blind; the engine posts blinds itself and it
+	// only appears in the log.
+	ActionPost ActionKind = iota
+	ActionFold
+	ActionCheck
+	ActionCall
+	ActionBet
+	ActionRaise
+)
+
+func (k ActionKind) String() string {
+	switch k {
+	case ActionFold:
+		return "fold"
+	case ActionCheck:
+		return "check"
+	case ActionCall:
+		return "call"
+	case ActionBet:
+		return "bet"
+	case ActionRaise:
+		return "raise"
+	default:
+		return "post"
+	}
+}
+
+// Action is a player's decision. For bets and raises Amount is the total the
+// player's bet on the street reaches; other kinds ignore it.
+type Action struct {
+	Kind   ActionKind
+	Amount int
+}
+
+// Event is one entry of the hand log.
+type Event struct {
+	Street Street
+	Seat   int
+	Kind   ActionKind
+	// Amount is the chips the action put in and To the player's bet on the
+	// street after it.
+	Amount int
+	To     int
+	AllIn  bool
+}
+
+// Deck deals the cards of a hand.
+type Deck interface {
+	Draw(n int) []poker.Card
+}
+
+// NewDeck returns a full deck that shuffles as it deals, drawing with
+// poker.DrawCards from rng.
+func NewDeck(rng *rand.Rand) Deck {
+	return &randomDeck{cards: poker.BuildDeck(nil), rng: rng}
+}
+
+type randomDeck struct {
+	cards []poker.Card
+	rng   *rand.Rand
+}
+
+func (d *randomDeck) Draw(n int) []poker.Card {
+	return poker.DrawCards(&d.cards, n, d.rng)
+}
+
+// Config sets up a hand.
+type Config struct {
+	// Stacks holds the chips of every seat; seats without chips sit the
+	// hand out.
+	Stacks     []int
+	Button     int
+	SmallBlind int
+	BigBlind   int
+	// Seed shuffles the deck when Deck is nil, so the config and the log
+	// are enough to replay a hand.
+	Seed int64
+	Deck Deck
+}
+
+// Player is one seat's part in the hand.
+type Player struct {
+	// Stack holds the chips behind, Bet the chips put in on the current
+	// street and Contributed every chip put in during the hand.
+	Stack       int
+	Bet         int
+	Contributed int
+	Hole        []poker.Card
+	SittingOut  bool
+	Folded      bool
+
+	// acted is set once the player acts on the street and faced holds the
+	// bet they left the street at.
+	acted bool
+	faced int
+}
+
+// Live reports whether the player can still win the pot.
+func (p Player) Live() bool {
+	return !p.SittingOut && !p.Folded
+}
+
+// AllIn reports whether a live player has no chips left to bet.
+func (p Player) AllIn() bool {
+	return p.Live() && p.Stack == 0
+}
+
+// Legal lists the actions open to the player to act. Folding is always
+// allowed.
+type Legal struct {
+	// Call is the chips needed to call, capped by the player's stack; zero
+	// means the player can check.
+	Call int
+	// CanRaise allows a bet, or a raise when there is a bet to call, to a
+	// street total between MinTo and MaxTo. MinTo equals MaxTo when the
+	// player can only move all-in.
+	CanRaise bool
+	MinTo    int
+	MaxTo    int
+}
+
+// Result settles a finished hand.
+type Result struct {
+	// Showdown is false when everyone but one player folded.
+	Showdown bool
+	// Pots lists the main pot first; Eligible and Winners hold seat numbers.
+	Pots []poker.Pot
+	// Hands holds the best hand of every seat at the showdown, and
+	// Winnings the chips each seat collects.
+	Hands    []poker.HandRank
+	Winnings []int
+}
+
+// Hand is a No-Limit Hold'em hand in progress.
+type Hand struct {
+	cfg     Config
+	deck    Deck
+	players []Player
+	board   []poker.Card
+	street  Street
+	toAct   int
+	// bet is the highest bet on the street, minRaise the size of the last
+	// full bet or raise, and fullTo the bet it reached. An all-in for less
+	// than a full raise does not reopen the betting for players who already
+	// acted at fullTo.
+	bet      int
+	minRaise int
+	fullTo   int
+	log      []Event
+	result   *Result
+}
+
+// New posts the blinds and deals the hole cards. With two players the button
+// posts the small blind and acts first before the flop; otherwise the two
+// players left of the button post the blinds.
+func New(cfg Config) (*Hand, error) {
+	n := len(cfg.Stacks)
+	if n < 2 || n > MaxSeats {
+		return nil, fmt.Errorf("a table seats 2 to %d players, got %d", MaxSeats, n)
+	}
+	if cfg.BigBlind <= 0 || cfg.SmallBlind < 0 || cfg.SmallBlind > cfg.BigBlind {
+		return nil, errors.New("blinds must be positive, the small blind no larger than the big one")
+	}
+	if cfg.Button < 0 || cfg.Button >= n {
+		return nil, fmt.Errorf("button seat %d is out of range", cfg.Button)
+	}
+
+	h := &Hand{cfg: cfg, deck: cfg.Deck, players: make([]Player, n), minRaise: cfg.BigBlind}
+	if h.deck == nil {
+		h.deck = NewDeck(rand.New(rand.NewSource(cfg.Seed)))
+	}
+	dealt := 0
+	for i, stack := range cfg.Stacks {
+		if stack < 0 {
+			return nil, errors.New("stacks cannot be negative")
+		}
+		h.players[i] = Player{Stack: stack, SittingOut: stack == 0}
+		if stack > 0 {
+			dealt++
+		}
+	}
+	if dealt < 2 {
+		return nil, errors.New("at least two players must have chips")
+	}
+	if h.players[cfg.Button].SittingOut {
+		return nil, errors.New("the button must be dealt in")
+	}
+
+	sb := h.after(cfg.Button)
+	if dealt == 2 {
+		sb = cfg.Button
+	}
+	bb := h.after(sb)
+	h.post(sb, cfg.SmallBlind)
+	h.post(bb, cfg.BigBlind)
+	h.bet, h.fullTo = cfg.BigBlind, cfg.BigBlind
+
+	for i, seat := 0, sb; i < dealt; i, seat = i+1, h.after(seat) {
+		hole := h.deck.Draw(2)
+		if len(hole) != 2 {
+			return nil, errors.New("the deck ran out of cards")
+		}
+		h.players[seat].Hole = hole
+	}
+	if err := h.advance(bb); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Replay plays the logged actions again from the config. The hand comes out
+// the same as long as the deck deals the same cards, which holds whenever
+// the config relies on Seed.
+func Replay(cfg Config, log []Event) (*Hand, error) {
+	h, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range log {
+		if e.Kind == ActionPost {
+			continue
+		}
+		if e.Seat != h.toAct {
+			return nil, fmt.Errorf("event %d: seat %d acts out of turn", i+1, e.Seat)
+		}
+		if err := h.Apply(Action{Kind: e.Kind, Amount: e.To}); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+	return h, nil
+}
+
+// Seats returns the number of seats at the table.
+func (h *Hand) Seats() int { return len(h.players) }
+
+// Player returns the state of a seat.
+func (h *Hand) Player(seat int) Player { return h.players[seat] }
+
+// Button returns the button seat.
+func (h *Hand) Button() int { return h.cfg.Button }
+
+// Street returns the current betting round.
+func (h *Hand) Street() Street { return h.street }
+
+// Board returns the community cards dealt so far.
+func (h *Hand) Board() []poker.Card { return append([]poker.Card(nil), h.board...) }
+
+// ToAct returns the seat to act, or -1 once the hand is over.
+func (h *Hand) ToAct() int { return h.toAct }
+
+// Done reports whether the hand is over.
+func (h *Hand) Done() bool { return h.result != nil }
+
+// Result returns the settlement of a finished hand.
+func (h *Hand) Result() Result {
+	if h.result == nil {
+		return Result{}
+	}
+	return *h.result
+}
+
+// Log returns the blinds and actions in the order they happened.
+func (h *Hand) Log() []Event { return append([]Event(nil), h.log...) }
+
+// Pot returns every chip put in so far, including the current street's bets.
+func (h *Hand) Pot() int {
+	pot := 0
+	for _, p := range h.players {
+		pot += p.Contributed
+	}
+	return pot
+}
+
+// Stacks returns the chips behind of every seat; once the hand is over they
+// include the winnings.
+func (h *Hand) Stacks() []int {
+	stacks := make([]int, len(h.players))
+	for i, p := range h.players {
+		stacks[i] = p.Stack
+	}
+	return stacks
+}
+
+// Legal returns the actions open to the player to act.
+func (h *Hand) Legal() Legal {
+	if h.Done() {
+		return Legal{}
+	}
+	p := h.players[h.toAct]
+	legal := Legal{Call: min(h.bet-p.Bet, p.Stack), MaxTo: p.Bet + p.Stack}
+	legal.MinTo = min(h.bet+h.minRaise, legal.MaxTo)
+
+	opponents := false
+	for i, o := range h.players {
+		if i != h.toAct && o.Live() && o.Stack > 0 {
+			opponents = true
+		}
+	}
+	legal.CanRaise = legal.MaxTo > h.bet && opponents && (!p.acted || h.fullTo > p.faced)
+	return legal
+}
+
+// Apply carries out the action of the player to act and moves the hand on,
+// dealing the next streets and settling the pot when betting ends.
+func (h *Hand) Apply(a Action) error {
+	if h.Done() {
+		return errors.New("the hand is over")
+	}
+	seat := h.toAct
+	p := &h.players[seat]
+	legal := h.Legal()
+	before := p.Bet
+
+	switch a.Kind {
+	case ActionFold:
+		p.Folded = true
+	case ActionCheck:
+		if legal.Call > 0 {
+			return fmt.Errorf("cannot check facing a bet of %d", legal.Call)
+		}
+	case ActionCall:
+		if legal.Call == 0 {
+			return errors.New("there is nothing to call, check instead")
+		}
+		h.put(seat, legal.Call)
+	case ActionBet, ActionRaise:
+		if a.Kind == ActionBet && h.bet > 0 {
+			return errors.New("cannot bet facing a bet, raise instead")
+		}
+		if a.Kind == ActionRaise && h.bet == 0 {
+			return errors.New("there is nothing to raise, bet instead")
+		}
+		if !legal.CanRaise {
+			return fmt.Errorf("cannot %s now", a.Kind)
+		}
+		if a.Amount < legal.MinTo || a.Amount > legal.MaxTo {
+			return fmt.Errorf("%s must reach %d to %d, got %d", a.Kind, legal.MinTo, legal.MaxTo, a.Amount)
+		}
+		if raise := a.Amount - h.bet; raise >= h.minRaise {
+			h.minRaise, h.fullTo = raise, a.Amount
+		}
+		h.put(seat, a.Amount-p.Bet)
+		h.bet = a.Amount
+	default:
+		return fmt.Errorf("unknown action %v", a.Kind)
+	}
+	p.acted, p.faced = true, h.bet
+	h.record(seat, a.Kind, p.Bet-before)
+	return h.advance(seat)
+}
+
+// post puts a blind in, or the player's whole stack when it is smaller.
+func (h *Hand) post(seat, amount int) {
+	amount = min(amount, h.players[seat].Stack)
+	h.put(seat, amount)
+	h.record(seat, ActionPost, amount)
+}
+
+// put moves chips from the player's stack into the pot.
+func (h *Hand) put(seat, amount int) {
+	p := &h.players[seat]
+	p.Stack -= amount
+	p.Bet += amount
+	p.Contributed += amount
+}
+
+func (h *Hand) record(seat int, kind ActionKind, amount int) {
+	p := h.players[seat]
+	h.log = append(h.log, Event{Street: h.street, Seat: seat, Kind: kind, Amount: amount, To: p.Bet, AllIn: amount > 0 && p.Stack == 0})
+}
+
+// after returns the next seat left of seat that was dealt in.
+func (h *Hand) after(seat int) int {
+	for i := 1; i < len(h.players); i++ {
+		next := (seat + i) % len(h.players)
+		if !h.players[next].SittingOut {
+			return next
+		}
+	}
+	return seat
+}
+
+// needsAction reports whether the player still has a decision on the street.
+func (h *Hand) needsAction(seat int) bool {
+	p := h.players[seat]
+	if !p.Live() || p.Stack == 0 {
+		return false
+	}
+	if p.acted && p.Bet == h.bet {
+		return false
+	}
+	// Alone against all-in players, a player who matched the bet has nothing
+	// left to decide.
+	if p.Bet >= h.bet {
+		for i, o := range h.players {
+			if i != seat && o.Live() && o.Stack > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// advance passes the turn to the next player left of seat who has to act,
+// or ends the street when nobody does.
+func (h *Hand) advance(seat int) error {
+	live := 0
+	for _, p := range h.players {
+		if p.Live() {
+			live++
+		}
+	}
+	if live == 1 {
+		h.award()
+		return nil
+	}
+	for i := 1; i <= len(h.players); i++ {
+		next := (seat + i) % len(h.players)
+		if h.needsAction(next) {
+			h.toAct = next
+			return nil
+		}
+	}
+	return h.nextStreet()
+}
+
+// nextStreet deals the following streets until a player has to act, running
+// the board out when everyone is all-in, and settles the showdown after the
+// river.
+func (h *Hand) nextStreet() error {
+	for h.street < River {
+		for i := range h.players {
+			h.players[i].Bet, h.players[i].acted, h.players[i].faced = 0, false, 0
+		}
+		h.bet, h.minRaise, h.fullTo = 0, h.cfg.BigBlind, 0
+		h.street++
+
+		n := 1
+		if h.street == Flop {
+			n = 3
+		}
+		cards := h.deck.Draw(n)
+		if len(cards) != n {
+			return errors.New("the deck ran out of cards")
+		}
+		h.board = append(h.board, cards...)
+
+		// After the flop the first player left of the button acts first.
+		for i := 1; i <= len(h.players); i++ {
+			seat := (h.cfg.Button + i) % len(h.players)
+			if h.needsAction(seat) {
+				h.toAct = seat
+				return nil
+			}
+		}
+	}
+	return h.showdown()
+}
+
+// award gives the whole pot to the last player who did not fold.
+func (h *Hand) award() {
+	res := Result{Winnings: make([]int, len(h.players))}
+	for i, p := range h.players {
+		if p.Live() {
+			pot := h.Pot()
+			res.Pots = []poker.Pot{{Amount: pot, Eligible: []int{i}, Winners: []int{i}}}
+			res.Winnings[i] = pot
+		}
+	}
+	h.settle(res)
+}
+
+// showdown splits the main and side pots with poker.ResolveShowdown, which
+// ranks hands with the same evaluator as poker.EvaluateBestHand. Players are
+// passed from the left of the button so odd chips go out in seat order.
+func (h *Hand) showdown() error {
+	var seats []int
+	cfg := poker.ShowdownConfig{Game: poker.GameHoldem, Board: h.board}
+	for i, seat := 0, h.after(h.cfg.Button); i < len(h.players); i, seat = i+1, (seat+1)%len(h.players) {
+		p := h.players[seat]
+		if p.SittingOut {
+			continue
+		}
+		seats = append(seats, seat)
+		cfg.Players = append(cfg.Players, poker.ShowdownPlayer{Hole: p.Hole, Contributed: p.Contributed, Folded: p.Folded})
+	}
+	resolved, err := poker.ResolveShowdown(cfg)
+	if err != nil {
+		return err
+	}
+
+	res := Result{Showdown: true, Hands: make([]poker.HandRank, len(h.players)), Winnings: make([]int, len(h.players))}
+	for i, seat := range seats {
+		res.Hands[seat] = resolved.Hands[i]
+		res.Winnings[seat] = resolved.Winnings[i]
+	}
+	for _, pot := range resolved.Pots {
+		for i := range pot.Eligible {
+			pot.Eligible[i] = seats[pot.Eligible[i]]
+		}
+		for i := range pot.Winners {
+			pot.Winners[i] = seats[pot.Winners[i]]
+		}
+		res.Pots = append(res.Pots, pot)
+	}
+	h.street = Showdown
+	h.settle(res)
+	return nil
+}
+
+func (h *Hand) settle(res Result) {
+	for i, won := range res.Winnings {
+		h.players[i].Stack += won
+	}
+	h.result = &res
+	h.toAct = -1
+}
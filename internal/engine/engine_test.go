@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+// stackedDeck deals fixed cards in order.
+type stackedDeck []poker.Card
+
+func (d *stackedDeck) Draw(n int) []poker.Card {
+	n = min(n, len(*d))
+	drawn := (*d)[:n]
+	*d = (*d)[n:]
+	return drawn
+}
+
+func deck(values ...string) *stackedDeck {
+	d := make(stackedDeck, len(values))
+	for i, v := range values {
+		c, err := poker.ParseCard(v)
+		if err != nil {
+			panic(err)
+		}
+		d[i] = c
+	}
+	return &d
+}
+
+func apply(t *testing.T, h *Hand, seat int, a Action) {
+	t.Helper()
+	if h.ToAct() != seat {
+		t.Fatalf("expected seat %d to act, got %d", seat, h.ToAct())
+	}
+	if err := h.Apply(a); err != nil {
+		t.Fatalf("seat %d %v: unexpected error: %v", seat, a.Kind, err)
+	}
+}
+
+func TestHeadsUpHand(t *testing.T) {
+	h, err := New(Config{
+		Stacks:     []int{1000, 1000},
+		SmallBlind: 5,
+		BigBlind:   10,
+		Deck:       deck("Ah", "Ad", "Kh", "Kd", "2c", "7d", "9h", "Ts", "3s"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The button posts the small blind and acts first before the flop.
+	if h.ToAct() != 0 || h.Pot() != 15 {
+		t.Fatalf("unexpected start: seat %d to act, pot %d", h.ToAct(), h.Pot())
+	}
+	if legal := h.Legal(); legal.Call != 5 || !legal.CanRaise || legal.MinTo != 20 || legal.MaxTo != 1000 {
+		t.Fatalf("unexpected legal actions %+v", legal)
+	}
+	if err := h.Apply(Action{Kind: ActionCheck}); err == nil {
+		t.Fatalf("expected an error when checking facing the big blind")
+	}
+	if err := h.Apply(Action{Kind: ActionRaise, Amount: 15}); err == nil {
+		t.Fatalf("expected an error for a raise below the minimum")
+	}
+	apply(t, h, 0, Action{Kind: ActionCall})
+	if legal := h.Legal(); legal.Call != 0 || !legal.CanRaise {
+		t.Fatalf("expected the big blind to keep the option, got %+v", legal)
+	}
+	apply(t, h, 1, Action{Kind: ActionCheck})
+
+	// After the flop the big blind acts first.
+	if h.Street() != Flop || len(h.Board()) != 3 || h.ToAct() != 1 {
+		t.Fatalf("unexpected flop: street %v, board %v, seat %d", h.Street(), h.Board(), h.ToAct())
+	}
+	apply(t, h, 1, Action{Kind: ActionBet, Amount: 20})
+	apply(t, h, 0, Action{Kind: ActionRaise, Amount: 60})
+	apply(t, h, 1, Action{Kind: ActionCall})
+	for range 2 {
+		apply(t, h, 1, Action{Kind: ActionCheck})
+		apply(t, h, 0, Action{Kind: ActionCheck})
+	}
+
+	if !h.Done() || h.Street() != Showdown || h.ToAct() != -1 {
+		t.Fatalf("expected the hand to reach the showdown")
+	}
+	res := h.Result()
+	if !res.Showdown || res.Winnings[0] != 140 || res.Hands[0].Category != poker.OnePair {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if stacks := h.Stacks(); stacks[0] != 1070 || stacks[1] != 930 {
+		t.Fatalf("unexpected stacks %v", stacks)
+	}
+	if err := h.Apply(Action{Kind: ActionCheck}); err == nil {
+		t.Fatalf("expected an error after the hand is over")
+	}
+}
+
+func TestFoldWinsPot(t *testing.T) {
+	h, err := New(Config{Stacks: []int{1000, 1000}, SmallBlind: 5, BigBlind: 10, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	apply(t, h, 0, Action{Kind: ActionRaise, Amount: 30})
+	apply(t, h, 1, Action{Kind: ActionFold})
+
+	res := h.Result()
+	if !h.Done() || res.Showdown || res.Winnings[0] != 40 || len(res.Pots) != 1 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if stacks := h.Stacks(); stacks[0] != 1010 || stacks[1] != 990 {
+		t.Fatalf("unexpected stacks %v", stacks)
+	}
+}
+
+func TestIncompleteRaiseDoesNotReopenBetting(t *testing.T) {
+	h, err := New(Config{Stacks: []int{1000, 1000, 130}, SmallBlind: 5, BigBlind: 10, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Three-handed the button is first to act before the flop.
+	apply(t, h, 0, Action{Kind: ActionCall})
+	apply(t, h, 1, Action{Kind: ActionCall})
+	apply(t, h, 2, Action{Kind: ActionCheck})
+
+	apply(t, h, 1, Action{Kind: ActionBet, Amount: 100})
+	if legal := h.Legal(); !legal.CanRaise || legal.MinTo != 120 || legal.MaxTo != 120 {
+		t.Fatalf("expected the short stack to only move all-in, got %+v", legal)
+	}
+	apply(t, h, 2, Action{Kind: ActionRaise, Amount: 120})
+	if legal := h.Legal(); !legal.CanRaise || legal.MinTo != 220 {
+		t.Fatalf("expected a player yet to act to raise from 220, got %+v", legal)
+	}
+	apply(t, h, 0, Action{Kind: ActionCall})
+
+	if legal := h.Legal(); legal.CanRaise || legal.Call != 20 {
+		t.Fatalf("expected the bettor to only call the short all-in, got %+v", legal)
+	}
+	if err := h.Apply(Action{Kind: ActionRaise, Amount: 300}); err == nil {
+		t.Fatalf("expected an error when raising after an incomplete raise")
+	}
+	apply(t, h, 1, Action{Kind: ActionCall})
+	if h.Street() != Turn || !h.Player(2).AllIn() || h.ToAct() != 1 {
+		t.Fatalf("expected the turn with the short stack all-in, got %v and seat %d", h.Street(), h.ToAct())
+	}
+}
+
+func TestAllInSidePots(t *testing.T) {
+	// Cards go out from the small blind: seat 1, seat 2, then the button.
+	h, err := New(Config{
+		Stacks:     []int{100, 300, 500},
+		SmallBlind: 5,
+		BigBlind:   10,
+		Deck:       deck("Kh", "Kd", "Qh", "Qd", "Ah", "Ad", "2c", "7d", "9h", "Ts", "3s"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	apply(t, h, 0, Action{Kind: ActionRaise, Amount: 100})
+	apply(t, h, 1, Action{Kind: ActionRaise, Amount: 300})
+	apply(t, h, 2, Action{Kind: ActionCall})
+
+	// Nobody is left to bet against the big blind, so the board runs out.
+	if !h.Done() || len(h.Board()) != 5 {
+		t.Fatalf("expected the board to run out, got %v", h.Board())
+	}
+	res := h.Result()
+	if len(res.Pots) != 2 || res.Pots[0].Amount != 300 || res.Pots[0].Winners[0] != 0 || res.Pots[1].Amount != 400 || res.Pots[1].Winners[0] != 1 {
+		t.Fatalf("unexpected pots %+v", res.Pots)
+	}
+	if stacks := h.Stacks(); !slices.Equal(stacks, []int{300, 400, 200}) {
+		t.Fatalf("unexpected stacks %v", stacks)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	cfg := Config{Stacks: []int{500, 0, 800, 650}, Button: 3, SmallBlind: 10, BigBlind: 20, Seed: 42}
+	h, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !h.Player(1).SittingOut || h.Player(1).Hole != nil {
+		t.Fatalf("expected the seat without chips to sit out")
+	}
+	for !h.Done() {
+		legal := h.Legal()
+		a := Action{Kind: ActionCheck}
+		switch {
+		case h.Street() == Flop && legal.CanRaise && legal.Call == 0:
+			a = Action{Kind: ActionBet, Amount: legal.MinTo}
+		case legal.Call > 0:
+			a = Action{Kind: ActionCall}
+		}
+		if err := h.Apply(a); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	again, err := Replay(cfg, h.Log())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !again.Done() || !slices.Equal(again.Board(), h.Board()) || !slices.Equal(again.Stacks(), h.Stacks()) || !slices.Equal(again.Log(), h.Log()) {
+		t.Fatalf("replay differs: board %v vs %v, stacks %v vs %v", again.Board(), h.Board(), again.Stacks(), h.Stacks())
+	}
+
+	log := h.Log()
+	log[2].Seat = 1
+	if _, err := Replay(cfg, log); err == nil {
+		t.Fatalf("expected an error for an action out of turn")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, cfg := range []Config{
+		{Stacks: []int{100}, SmallBlind: 5, BigBlind: 10},
+		{Stacks: []int{100, 100}, SmallBlind: 10, BigBlind: 5},
+		{Stacks: []int{100, 0}, SmallBlind: 5, BigBlind: 10},
+		{Stacks: []int{0, 100, 100}, SmallBlind: 5, BigBlind: 10},
+		{Stacks: []int{100, 100}, Button: 2, SmallBlind: 5, BigBlind: 10},
+		{Stacks: []int{100, 100}, SmallBlind: 5, BigBlind: 10, Deck: deck("Ah", "Kd")},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
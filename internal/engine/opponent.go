@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"pokerbot/internal/poker"
+)
+
+// DefaultOpponentTrials is the number of simulations behind each decision
+// when the opponent leaves Trials at zero.
+const DefaultOpponentTrials = 3000
+
+// Opponent is a computer player that weighs its equity against the price the
+// pot offers.
+type Opponent struct {
+	// Aggression from 0 to 1 sets how often and how big the opponent bets,
+	// raises and bluffs.
+	Aggression float64
+	Trials     int
+}
+
+// Decide chooses the action of the player to act. Equity comes from
+// poker.SimulateWinProbability against random hands of every other live
+// player, always sampled since enumerating a flop takes about a million
+// showdowns; a bet is called when poker.Advise finds the call profitable, and
+// strong hands bet or raise, sized by aggression. Bluffs are drawn from rng.
+func (o Opponent) Decide(ctx context.Context, h *Hand, rng *rand.Rand) (Action, error) {
+	if h.Done() {
+		return Action{}, errors.New("the hand is over")
+	}
+	seat := h.ToAct()
+	p := h.Player(seat)
+	opponents := 0
+	for i := range h.Seats() {
+		if i != seat && h.Player(i).Live() {
+			opponents++
+		}
+	}
+	trials := o.Trials
+	if trials == 0 {
+		trials = DefaultOpponentTrials
+	}
+	result, err := poker.SimulateWinProbabilityContext(ctx, poker.SimulationConfig{
+		Hero:       p.Hole,
+		Board:      h.Board(),
+		Opponents:  opponents,
+		Style:      poker.StyleAny,
+		Trials:     trials,
+		Seed:       rng.Int63(),
+		ExactLimit: -1,
+	})
+	if err != nil {
+		return Action{}, err
+	}
+	return o.choose(result.Equity, h, rng), nil
+}
+
+// choose turns equity, in percent, into an action. Value bets start at 60%
+// equity for a passive opponent and at 45% for the most aggressive one;
+// raises need 15 to 25 points more than the pot odds ask for.
+func (o Opponent) choose(equity float64, h *Hand, rng *rand.Rand) Action {
+	a := min(max(o.Aggression, 0), 1)
+	legal := h.Legal()
+	pot := h.Pot()
+	bet := h.Player(h.ToAct()).Bet + legal.Call
+	// size is a bet or raise to a fraction of the pot after calling,
+	// growing from half the pot to the full pot with aggression.
+	size := func() Action {
+		kind := ActionBet
+		if bet > 0 {
+			kind = ActionRaise
+		}
+		to := bet + int(float64(pot+legal.Call)*(0.5+0.5*a))
+		return Action{Kind: kind, Amount: min(max(to, legal.MinTo), legal.MaxTo)}
+	}
+
+	if legal.Call == 0 {
+		if legal.CanRaise && (equity >= 60-15*a || rng.Float64() < 0.3*a) {
+			return size()
+		}
+		return Action{Kind: ActionCheck}
+	}
+
+	advice, err := poker.Advise(equity, poker.Decision{
+		Pot:         float64(pot),
+		Call:        float64(legal.Call),
+		Stack:       float64(h.Player(h.ToAct()).Stack),
+		CardsToCome: 5 - len(h.Board()),
+	})
+	if err != nil {
+		return Action{Kind: ActionFold}
+	}
+	if legal.CanRaise && (equity >= advice.RequiredEquity+25-10*a || rng.Float64() < 0.1*a) {
+		return size()
+	}
+	if advice.Recommendation != poker.RecommendFold {
+		return Action{Kind: ActionCall}
+	}
+	return Action{Kind: ActionFold}
+}
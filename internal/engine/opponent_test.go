@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestOpponentChoose(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	newHand := func() *Hand {
+		h, err := New(Config{
+			Stacks:     []int{1000, 1000},
+			SmallBlind: 5,
+			BigBlind:   10,
+			Deck:       deck("Ah", "Ad", "7c", "2d", "Kh", "9s", "4c", "Td", "3s"),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return h
+	}
+	passive := Opponent{}
+
+	h := newHand()
+	if a := passive.choose(85, h, rng); a.Kind != ActionRaise || a.Amount < 20 {
+		t.Fatalf("expected a raise with a strong hand, got %+v", a)
+	}
+	if a := passive.choose(40, h, rng); a.Kind != ActionCall {
+		t.Fatalf("expected the small blind to complete at a good price, got %+v", a)
+	}
+
+	// Facing a pot-sized all-in, a weak hand folds.
+	apply(t, h, 0, Action{Kind: ActionRaise, Amount: 1000})
+	if a := passive.choose(20, h, rng); a.Kind != ActionFold {
+		t.Fatalf("expected a fold against an all-in, got %+v", a)
+	}
+	if a := passive.choose(60, h, rng); a.Kind != ActionCall {
+		t.Fatalf("expected a call of the all-in with 60%% equity, got %+v", a)
+	}
+
+	h = newHand()
+	apply(t, h, 0, Action{Kind: ActionCall})
+	if a := passive.choose(30, h, rng); a.Kind != ActionCheck {
+		t.Fatalf("expected the big blind to check a weak hand, got %+v", a)
+	}
+}
+
+func TestOpponentPlaysHands(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	players := []Opponent{{Aggression: 0.9, Trials: 300}, {Aggression: 0.1, Trials: 300}}
+	stacks := []int{500, 500, 500}
+	for hand := range 5 {
+		h, err := New(Config{Stacks: stacks, Button: hand % 3, SmallBlind: 5, BigBlind: 10, Seed: int64(hand)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for !h.Done() {
+			a, err := players[h.ToAct()%2].Decide(context.Background(), h, rng)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := h.Apply(a); err != nil {
+				t.Fatalf("illegal decision %+v: %v", a, err)
+			}
+		}
+		stacks = h.Stacks()
+		if total := stacks[0] + stacks[1] + stacks[2]; total != 1500 {
+			t.Fatalf("chips were lost: %v", stacks)
+		}
+		if min(stacks[0], stacks[1], stacks[2]) == 0 {
+			break
+		}
+	}
+}
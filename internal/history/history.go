@@ -0,0 +1,404 @@
+// Package history reads PokerStars and GGPoker text hand histories and
+// replays them street by street.
+package history
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"pokerbot/internal/poker"
+)
+
+// Site identifies the poker room a hand history comes from.
+type Site int
+
+const (
+	PokerStars Site = iota
+	GGPoker
+)
+
+func (s Site) String() string {
+	if s == GGPoker {
+		return "GGPoker"
+	}
+	return "PokerStars"
+}
+
+// Street is a betting round.
+type Street int
+
+const (
+	Preflop Street = iota
+	Flop
+	Turn
+	River
+)
+
+// boardSize returns the number of board cards dealt by the street.
+func (s Street) boardSize() int {
+	switch s {
+	case Flop:
+		return 3
+	case Turn:
+		return 4
+	case River:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// ActionKind enumerates what a player can do in a hand history.
+type ActionKind int
+
+const (
+	ActionPost ActionKind = iota
+	ActionFold
+	ActionCheck
+	ActionCall
+	ActionBet
+	ActionRaise
+	// ActionReturn gives back the part of a bet nobody called.
+	ActionReturn
+)
+
+// Action is one line of play.
+type Action struct {
+	Street Street
+	Player string
+	Kind   ActionKind
+	// Amount is the chips the action adds to the pot, or takes back for
+	// ActionReturn. For raises To holds the player's total bet on the street.
+	Amount float64
+	To     float64
+	AllIn  bool
+}
+
+// Seat is a player sitting at the table when the hand started.
+type Seat struct {
+	Number int
+	Player string
+	Stack  float64
+	// SittingOut players are not dealt in.
+	SittingOut bool
+}
+
+// Hand is a parsed hand history.
+type Hand struct {
+	Site  Site
+	ID    string
+	Game  poker.Game
+	Table string
+	// Stakes are the blinds as written in the header, such as "$0.05/$0.10".
+	Stakes string
+	// Button is the seat number of the dealer.
+	Button int
+	Seats  []Seat
+	Hero   string
+	// Cards holds the hole cards of the hero and of every player who showed
+	// or mucked face up.
+	Cards   map[string][]poker.Card
+	Board   []poker.Card
+	Actions []Action
+	// Won holds the chips each player collected from the pot.
+	Won map[string]float64
+}
+
+// BoardAt returns the board cards visible on the street.
+func (h Hand) BoardAt(s Street) []poker.Card {
+	return h.Board[:min(s.boardSize(), len(h.Board))]
+}
+
+// Streets returns the streets the hand reached, preflop included.
+func (h Hand) Streets() []Street {
+	streets := []Street{Preflop}
+	for s := Flop; s <= River && len(h.Board) >= s.boardSize(); s++ {
+		streets = append(streets, s)
+	}
+	return streets
+}
+
+var (
+	starsHeader  = regexp.MustCompile(`^PokerStars (?:Hand|Game|Zoom Hand) #(\d+):\s*(?:Tournament #\d+,\s*)?(.+?)\s+\(?([^()]*?/[^()]*?)\)?\s+-\s`)
+	ggHeader     = regexp.MustCompile(`^Poker Hand #(\w+):\s*(?:Tournament #\d+,\s*)?(.+?)\s+\(([^()]*?/[^()]*?)\)\s+-\s`)
+	tableLine    = regexp.MustCompile(`^Table '([^']*)'.*?Seat #(\d+) is the button`)
+	seatLine     = regexp.MustCompile(`^Seat (\d+): (.+?) \(([^)]*?) in chips[^)]*\)(.*)$`)
+	streetLine   = regexp.MustCompile(`^\*\*\* (HOLE CARDS|FLOP|TURN|RIVER|SHOW ?DOWN|SUMMARY) \*\*\*(.*)$`)
+	dealtLine    = regexp.MustCompile(`^Dealt to (.+?) \[([^\]]+)\]`)
+	actionLine   = regexp.MustCompile(`^(.+?): (posts|folds|checks|calls|bets|raises|shows|mucks)\b(.*)$`)
+	returnLine   = regexp.MustCompile(`^Uncalled bet \((.+?)\) returned to (.+)$`)
+	collectLine  = regexp.MustCompile(`^(.+?) collected (\S+) from`)
+	summaryCards = regexp.MustCompile(`^Seat \d+: (.+?)(?: \([^)]*\))* (?:showed|mucked) \[([^\]]+)\]`)
+	boardLine    = regexp.MustCompile(`^Board \[([^\]]+)\]`)
+	bracketed    = regexp.MustCompile(`\[([^\]]+)\]`)
+	raiseAmounts = regexp.MustCompile(`^\s*(\S+) to (\S+)`)
+	firstAmount  = regexp.MustCompile(`^\s*(?:small blind |big blind |the ante |small & big blinds |straddle )?(\S+)`)
+)
+
+// Parse reads every hand in a PokerStars or GGPoker hand history file.
+// Hands are separated by their header lines; text around them is ignored.
+func Parse(text string) ([]Hand, error) {
+	var hands []Hand
+	var current []string
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		h, err := parseHand(current)
+		if err != nil {
+			return err
+		}
+		hands = append(hands, h)
+		current = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		header := starsHeader.MatchString(line) || ggHeader.MatchString(line)
+		if header {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		if line != "" && (header || len(current) > 0) {
+			current = append(current, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(hands) == 0 {
+		return nil, errors.New("no PokerStars or GGPoker hand found")
+	}
+	return hands, nil
+}
+
+// parseHand reads the lines of a single hand, starting with its header.
+func parseHand(lines []string) (Hand, error) {
+	h := Hand{Cards: make(map[string][]poker.Card), Won: make(map[string]float64)}
+
+	var gameName string
+	if m := starsHeader.FindStringSubmatch(lines[0]); m != nil {
+		h.Site, h.ID, gameName, h.Stakes = PokerStars, m[1], m[2], m[3]
+	} else {
+		m := ggHeader.FindStringSubmatch(lines[0])
+		h.Site, h.ID, gameName, h.Stakes = GGPoker, m[1], m[2], m[3]
+	}
+	game, err := parseGame(gameName)
+	if err != nil {
+		return Hand{}, fmt.Errorf("hand #%s: %w", h.ID, err)
+	}
+	h.Game = game
+
+	street := Preflop
+	committed := make(map[string]float64)
+	for _, line := range lines[1:] {
+		if err := h.parseLine(line, &street, committed); err != nil {
+			return Hand{}, fmt.Errorf("hand #%s: %s: %w", h.ID, line, err)
+		}
+	}
+
+	if len(h.Seats) < 2 {
+		return Hand{}, fmt.Errorf("hand #%s: expected at least two seats", h.ID)
+	}
+	if h.Hero == "" {
+		return Hand{}, fmt.Errorf("hand #%s: the hero's cards are missing", h.ID)
+	}
+	return h, nil
+}
+
+// parseLine applies one line of the hand body; street and committed track the
+// current betting round and what each player has put in during it.
+func (h *Hand) parseLine(line string, street *Street, committed map[string]float64) error {
+	if m := streetLine.FindStringSubmatch(line); m != nil {
+		next := map[string]Street{"FLOP": Flop, "TURN": Turn, "RIVER": River}
+		if s, ok := next[m[1]]; ok {
+			var board []poker.Card
+			for _, group := range bracketed.FindAllStringSubmatch(m[2], -1) {
+				cards, err := parseCards(group[1])
+				if err != nil {
+					return err
+				}
+				board = append(board, cards...)
+			}
+			if len(board) != s.boardSize() {
+				return fmt.Errorf("expected %d board cards, got %d", s.boardSize(), len(board))
+			}
+			*street = s
+			h.Board = board
+			clear(committed)
+		}
+		return nil
+	}
+
+	if m := tableLine.FindStringSubmatch(line); m != nil {
+		h.Table = m[1]
+		h.Button, _ = strconv.Atoi(m[2])
+		return nil
+	}
+	if m := seatLine.FindStringSubmatch(line); m != nil && *street == Preflop && len(h.Actions) == 0 {
+		number, _ := strconv.Atoi(m[1])
+		stack, err := parseAmount(m[3])
+		if err != nil {
+			return err
+		}
+		h.Seats = append(h.Seats, Seat{
+			Number:     number,
+			Player:     m[2],
+			Stack:      stack,
+			SittingOut: strings.Contains(m[4], "sitting out"),
+		})
+		return nil
+	}
+	if m := dealtLine.FindStringSubmatch(line); m != nil {
+		cards, err := parseCards(m[2])
+		if err != nil {
+			return err
+		}
+		if h.Hero == "" {
+			h.Hero = m[1]
+		}
+		h.Cards[m[1]] = cards
+		return nil
+	}
+	if m := returnLine.FindStringSubmatch(line); m != nil {
+		amount, err := parseAmount(m[1])
+		if err != nil {
+			return err
+		}
+		h.Actions = append(h.Actions, Action{Street: *street, Player: m[2], Kind: ActionReturn, Amount: amount})
+		return nil
+	}
+	if m := collectLine.FindStringSubmatch(line); m != nil {
+		amount, err := parseAmount(m[2])
+		if err != nil {
+			return err
+		}
+		h.Won[m[1]] += amount
+		return nil
+	}
+	if m := summaryCards.FindStringSubmatch(line); m != nil {
+		cards, err := parseCards(m[2])
+		if err != nil {
+			return err
+		}
+		h.Cards[m[1]] = cards
+		return nil
+	}
+	if m := boardLine.FindStringSubmatch(line); m != nil {
+		cards, err := parseCards(m[1])
+		if err != nil {
+			return err
+		}
+		if len(cards) > len(h.Board) {
+			h.Board = cards
+		}
+		return nil
+	}
+	if m := actionLine.FindStringSubmatch(line); m != nil {
+		return h.parseAction(m[1], m[2], m[3], *street, committed)
+	}
+	return nil
+}
+
+// parseAction records a player's action; rest is the text after the verb.
+func (h *Hand) parseAction(player, verb, rest string, street Street, committed map[string]float64) error {
+	a := Action{Street: street, Player: player, AllIn: strings.Contains(rest, "all-in")}
+	switch verb {
+	case "folds":
+		a.Kind = ActionFold
+	case "checks":
+		a.Kind = ActionCheck
+	case "shows", "mucks":
+		if m := bracketed.FindStringSubmatch(rest); m != nil {
+			cards, err := parseCards(m[1])
+			if err != nil {
+				return err
+			}
+			h.Cards[player] = cards
+		}
+		return nil
+	case "raises":
+		m := raiseAmounts.FindStringSubmatch(rest)
+		if m == nil {
+			return errors.New("expected a raise amount")
+		}
+		to, err := parseAmount(m[2])
+		if err != nil {
+			return err
+		}
+		a.Kind, a.To, a.Amount = ActionRaise, to, to-committed[player]
+	default:
+		m := firstAmount.FindStringSubmatch(rest)
+		if m == nil {
+			return errors.New("expected an amount")
+		}
+		amount, err := parseAmount(m[1])
+		if err != nil {
+			return err
+		}
+		a.Amount = amount
+		a.Kind = map[string]ActionKind{"posts": ActionPost, "calls": ActionCall, "bets": ActionBet}[verb]
+		// Antes are dead money and do not count towards the street's bet.
+		if strings.Contains(rest, "ante") {
+			h.Actions = append(h.Actions, a)
+			return nil
+		}
+	}
+	committed[player] += a.Amount
+	h.Actions = append(h.Actions, a)
+	return nil
+}
+
+// parseGame maps the game name of a header, such as "Hold'em No Limit", to
+// the variant.
+func parseGame(name string) (poker.Game, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "5 card omaha") || strings.Contains(lower, "plo5"):
+		return poker.GameOmaha5, nil
+	case strings.Contains(lower, "omaha hi/lo") || strings.Contains(lower, "stud") || strings.Contains(lower, "draw"):
+		return 0, fmt.Errorf("unsupported game: %s", name)
+	case strings.Contains(lower, "omaha"):
+		return poker.GameOmaha4, nil
+	case strings.Contains(lower, "short deck") || strings.Contains(lower, "6+"):
+		return poker.GameShortDeck, nil
+	case strings.Contains(lower, "hold'em") || strings.Contains(lower, "holdem"):
+		return poker.GameHoldem, nil
+	default:
+		return 0, fmt.Errorf("unsupported game: %s", name)
+	}
+}
+
+// parseAmount reads chips written as "$0.30", "€1,250.50" or "1500".
+func parseAmount(text string) (float64, error) {
+	cleaned := strings.Trim(text, "$€£¥₹ ")
+	cleaned = strings.ReplaceAll(cleaned, ",", "")
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid amount: %s", text)
+	}
+	return amount, nil
+}
+
+func parseCards(text string) ([]poker.Card, error) {
+	var cards []poker.Card
+	for _, field := range strings.Fields(text) {
+		c, err := poker.ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
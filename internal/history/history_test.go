@@ -0,0 +1,184 @@
+package history
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"pokerbot/internal/poker"
+)
+
+const starsHand = `PokerStars Hand #254823456789:  Hold'em No Limit ($0.05/$0.10 USD) - 2024/01/15 20:31:07 ET
+Table 'Acamar IV' 6-max Seat #3 is the button
+Seat 1: player1 ($10.23 in chips)
+Seat 2: Hero ($10 in chips)
+Seat 3: player3 ($9.85 in chips)
+Seat 4: player4 ($12 in chips) is sitting out
+player1: posts small blind $0.05
+Hero: posts big blind $0.10
+*** HOLE CARDS ***
+Dealt to Hero [Ah Kd]
+player3: raises $0.20 to $0.30
+player1: folds
+Hero: calls $0.20
+*** FLOP *** [Qh Jh 2c]
+Hero: checks
+player3: bets $0.40
+Hero: calls $0.40
+*** TURN *** [Qh Jh 2c] [Td]
+Hero: checks
+player3: bets $1
+Hero: raises $2 to $3
+player3: calls $2
+*** RIVER *** [Qh Jh 2c Td] [3s]
+Hero: bets $5.70 and is all-in
+player3: calls $5.45 and is all-in
+Uncalled bet ($0.25) returned to Hero
+*** SHOW DOWN ***
+Hero: shows [Ah Kd] (a straight, Ten to Ace)
+player3: shows [Qd Qc] (three of a kind, Queens)
+Hero collected $19.20 from pot
+*** SUMMARY ***
+Total pot $19.75 | Rake $0.55
+Board [Qh Jh 2c Td 3s]
+Seat 1: player1 (small blind) folded before Flop
+Seat 2: Hero (big blind) showed [Ah Kd] and won ($19.20) with a straight, Ten to Ace
+Seat 3: player3 (button) showed [Qd Qc] and lost with three of a kind, Queens
+`
+
+const ggHand = `Poker Hand #HD1234567: Hold'em No Limit ($0.02/$0.05) - 2024/02/01 12:00:00
+Table 'RushAndCash1' 6-max Seat #1 is the button
+Seat 1: 7a8b9c0d ($5 in chips)
+Seat 2: Hero ($5.25 in chips)
+7a8b9c0d: posts small blind $0.02
+Hero: posts big blind $0.05
+*** HOLE CARDS ***
+Dealt to 7a8b9c0d
+Dealt to Hero [9s 9d]
+7a8b9c0d: raises $0.10 to $0.15
+Hero: folds
+Uncalled bet ($0.10) returned to 7a8b9c0d
+*** SHOWDOWN ***
+7a8b9c0d collected $0.10 from pot
+*** SUMMARY ***
+Total pot $0.10 | Rake $0
+Seat 1: 7a8b9c0d (button) (small blind) collected ($0.10)
+Seat 2: Hero (big blind) folded before Flop
+`
+
+func TestParsePokerStars(t *testing.T) {
+	hands, err := Parse(starsHand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hands) != 1 {
+		t.Fatalf("expected one hand, got %d", len(hands))
+	}
+	h := hands[0]
+	if h.Site != PokerStars || h.ID != "254823456789" || h.Game != poker.GameHoldem || h.Stakes != "$0.05/$0.10 USD" {
+		t.Fatalf("unexpected header %+v", h)
+	}
+	if h.Table != "Acamar IV" || h.Button != 3 || len(h.Seats) != 4 || !h.Seats[3].SittingOut || h.Seats[0].Stack != 10.23 {
+		t.Fatalf("unexpected seats %+v", h.Seats)
+	}
+	if h.Hero != "Hero" || len(h.Cards["Hero"]) != 2 || len(h.Cards["player3"]) != 2 {
+		t.Fatalf("unexpected cards %v", h.Cards)
+	}
+	if len(h.Board) != 5 || len(h.BoardAt(Turn)) != 4 || len(h.Streets()) != 4 {
+		t.Fatalf("unexpected board %v", h.Board)
+	}
+
+	var turnRaise Action
+	for _, a := range h.Actions {
+		if a.Street == Turn && a.Kind == ActionRaise {
+			turnRaise = a
+		}
+	}
+	if turnRaise.Player != "Hero" || turnRaise.To != 3 || turnRaise.Amount != 3 {
+		t.Fatalf("unexpected turn raise %+v", turnRaise)
+	}
+	if h.Actions[2].Kind != ActionRaise || h.Actions[2].Amount != 0.30 {
+		t.Fatalf("unexpected preflop raise %+v", h.Actions[2])
+	}
+	if h.Won["Hero"] != 19.20 {
+		t.Fatalf("unexpected winnings %v", h.Won)
+	}
+}
+
+func TestParseGGPoker(t *testing.T) {
+	hands, err := Parse("\ufeff" + ggHand + "\n\n" + starsHand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hands) != 2 {
+		t.Fatalf("expected two hands, got %d", len(hands))
+	}
+	h := hands[0]
+	if h.Site != GGPoker || h.ID != "HD1234567" || h.Hero != "Hero" || len(h.Cards) != 1 {
+		t.Fatalf("unexpected hand %+v", h)
+	}
+	if len(h.Streets()) != 1 {
+		t.Fatalf("expected the hand to end preflop, got %v", h.Streets())
+	}
+	// The small blind raised to 0.15 after posting 0.02.
+	if a := h.Actions[2]; a.Kind != ActionRaise || math.Abs(a.Amount-0.13) > 1e-9 {
+		t.Fatalf("unexpected raise %+v", a)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"just some text",
+		"PokerStars Hand #1: Razz ($1/$2 USD) - 2024/01/01\nSeat 1: a ($1 in chips)\nSeat 2: b ($1 in chips)\nDealt to a [Ah Kd]",
+		"PokerStars Hand #1: Hold'em No Limit ($1/$2 USD) - 2024/01/01\nSeat 1: a ($1 in chips)\nSeat 2: b ($1 in chips)",
+		"PokerStars Hand #1: Hold'em No Limit ($1/$2 USD) - 2024/01/01\nSeat 1: a ($1 in chips)\nSeat 2: b ($1 in chips)\nDealt to a [Ah Kd]\n*** FLOP *** [Qh Jh]",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Fatalf("expected error for %q", text)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	hands, err := Parse(starsHand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reports, err := Replay(context.Background(), hands[0], ReplayConfig{Trials: 5000, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 4 {
+		t.Fatalf("expected a report per street, got %d", len(reports))
+	}
+
+	preflop := reports[0]
+	if len(preflop.Opponents) != 2 || preflop.Pot != 0 || len(preflop.Actions) != 5 {
+		t.Fatalf("unexpected preflop report %+v", preflop)
+	}
+	flop := reports[1]
+	if len(flop.Opponents) != 1 || flop.Opponents[0] != "player3" || math.Abs(flop.Pot-0.65) > 1e-9 {
+		t.Fatalf("unexpected flop report %+v", flop)
+	}
+	// The villain's queens are known from the showdown: the hero trails the
+	// set on the flop and holds the winning straight on the river.
+	if flop.Result.Equity > 40 {
+		t.Fatalf("expected the hero to trail the set on the flop, got %.2f", flop.Result.Equity)
+	}
+	if river := reports[3]; river.Result.Win != 100 || river.Result.Method != poker.MethodExact {
+		t.Fatalf("expected a certain win on the river, got %+v", river.Result)
+	}
+
+	gg, err := Parse(ggHand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reports, err = Replay(context.Background(), gg[0], ReplayConfig{Trials: 2000, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Result.Samples == 0 {
+		t.Fatalf("expected only the preflop street, got %+v", reports)
+	}
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"errors"
+
+	"pokerbot/internal/poker"
+)
+
+// DefaultReplayTrials is the number of simulations per street when the
+// replay config leaves Trials at zero.
+const DefaultReplayTrials = 20000
+
+// ReplayConfig sets how opponents whose cards stayed hidden are modelled.
+type ReplayConfig struct {
+	Style  poker.PlayerStyle
+	Range  poker.Range
+	Trials int
+	Seed   int64
+}
+
+// StreetReport is the hero's position at the start of a street.
+type StreetReport struct {
+	Street Street
+	Board  []poker.Card
+	// Pot holds the chips in the middle before the street's betting.
+	Pot float64
+	// Opponents lists the players still in the hand, and Actions the play
+	// on the street.
+	Opponents []string
+	Actions   []Action
+	Result    poker.SimulationResult
+}
+
+// Replay computes the hero's equity at the start of every street the hero
+// played. Opponents who showed their cards hold them; the rest are dealt
+// from cfg.Range, or by cfg.Style when the range is empty.
+func Replay(ctx context.Context, h Hand, cfg ReplayConfig) ([]StreetReport, error) {
+	hero, ok := h.Cards[h.Hero]
+	if !ok {
+		return nil, errors.New("the hero's cards are missing")
+	}
+	if cfg.Trials == 0 {
+		cfg.Trials = DefaultReplayTrials
+	}
+
+	folded := make(map[string]bool)
+	for _, s := range h.Seats {
+		if s.SittingOut {
+			folded[s.Player] = true
+		}
+	}
+
+	var reports []StreetReport
+	var pot float64
+	next := 0
+	for _, street := range h.Streets() {
+		report := StreetReport{Street: street, Board: h.BoardAt(street), Pot: pot}
+		for ; next < len(h.Actions) && h.Actions[next].Street == street; next++ {
+			report.Actions = append(report.Actions, h.Actions[next])
+		}
+		for _, s := range h.Seats {
+			if s.Player != h.Hero && !folded[s.Player] {
+				report.Opponents = append(report.Opponents, s.Player)
+			}
+		}
+		if folded[h.Hero] || len(report.Opponents) == 0 {
+			break
+		}
+
+		result, err := poker.SimulateWinProbabilityContext(ctx, h.simulationConfig(cfg, hero, report))
+		if err != nil {
+			return nil, err
+		}
+		report.Result = result
+		reports = append(reports, report)
+
+		for _, a := range report.Actions {
+			switch a.Kind {
+			case ActionFold:
+				folded[a.Player] = true
+			case ActionReturn:
+				pot -= a.Amount
+			default:
+				pot += a.Amount
+			}
+		}
+	}
+	return reports, nil
+}
+
+// simulationConfig describes the street as a simulation against every
+// opponent still in the hand.
+func (h Hand) simulationConfig(cfg ReplayConfig, hero []poker.Card, report StreetReport) poker.SimulationConfig {
+	in := make(map[string]bool)
+	seats := make([]poker.Seat, len(report.Opponents))
+	for i, name := range report.Opponents {
+		in[name] = true
+		switch cards, shown := h.Cards[name]; {
+		case shown:
+			seats[i].Cards = cards
+		case !cfg.Range.IsEmpty() && !h.Game.IsOmaha():
+			seats[i].Range = cfg.Range
+		default:
+			seats[i].Style = cfg.Style
+		}
+	}
+	// Cards shown by players who folded are out of play.
+	var dead []poker.Card
+	for name, cards := range h.Cards {
+		if name != h.Hero && !in[name] {
+			dead = append(dead, cards...)
+		}
+	}
+	return poker.SimulationConfig{
+		Game:   h.Game,
+		Hero:   hero,
+		Board:  report.Board,
+		Dead:   dead,
+		Seats:  seats,
+		Trials: cfg.Trials,
+		Seed:   cfg.Seed,
+	}
+}
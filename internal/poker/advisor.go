@@ -0,0 +1,78 @@
+package poker
+
+import "errors"
+
+// Recommendation is the advised action facing a bet.
+type Recommendation int
+
+const (
+	RecommendFold Recommendation = iota
+	RecommendCall
+	// RecommendCallImplied marks a call that loses on the current pot but
+	// breaks even if the hero wins enough more on later streets.
+	RecommendCallImplied
+)
+
+// Decision describes a bet the hero faces.
+type Decision struct {
+	// Pot is the pot before the hero acts, including the bet to call.
+	Pot  float64
+	Call float64
+	// Stack is the hero's effective stack before calling; zero when unknown.
+	Stack float64
+	// CardsToCome is the number of board cards still to be dealt.
+	CardsToCome int
+}
+
+// Advice combines equity with pot odds.
+type Advice struct {
+	// Call is the amount actually called, capped by the stack.
+	Call float64
+	// RequiredEquity is the break-even equity of a call, in percent.
+	RequiredEquity float64
+	// CallEV is the expected profit of calling relative to folding, which is
+	// always worth zero.
+	CallEV float64
+	// ImpliedNeeded is how much more the hero must win on later streets for a
+	// call to break even, zero when calling already is. A call is only
+	// recommended on implied odds when the stack is known to cover it.
+	ImpliedNeeded  float64
+	Recommendation Recommendation
+}
+
+// Advise compares equity, in percent with ties already split, against the
+// price of calling. A call too large for the stack is treated as an all-in for
+// the stack, with the uncalled part of the bet returned.
+func Advise(equity float64, d Decision) (Advice, error) {
+	if equity < 0 || equity > 100 {
+		return Advice{}, errors.New("equity must be between 0 and 100")
+	}
+	if d.Call <= 0 || d.Pot < d.Call || d.Stack < 0 {
+		return Advice{}, errors.New("pot must include a positive bet to call")
+	}
+
+	pot, call := d.Pot, d.Call
+	if d.Stack > 0 && call > d.Stack {
+		pot -= call - d.Stack
+		call = d.Stack
+	}
+
+	e := equity / 100
+	a := Advice{
+		Call:           call,
+		RequiredEquity: call / (pot + call) * 100,
+		CallEV:         e*(pot+call) - call,
+	}
+	switch {
+	case a.CallEV >= 0:
+		a.Recommendation = RecommendCall
+	case e > 0:
+		a.ImpliedNeeded = call/e - pot - call
+	}
+
+	// Implied odds need cards to come and chips left behind to win.
+	if a.ImpliedNeeded > 0 && d.CardsToCome > 0 && a.ImpliedNeeded <= d.Stack-call {
+		a.Recommendation = RecommendCallImplied
+	}
+	return a, nil
+}
@@ -0,0 +1,63 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdvisePotOdds(t *testing.T) {
+	advice, err := Advise(40, Decision{Pot: 120, Call: 40, Stack: 900})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.RequiredEquity != 25 || math.Abs(advice.CallEV-24) > 1e-9 {
+		t.Fatalf("unexpected advice %+v", advice)
+	}
+	if advice.Recommendation != RecommendCall || advice.ImpliedNeeded != 0 {
+		t.Fatalf("expected a plain call, got %+v", advice)
+	}
+}
+
+func TestAdviseImpliedOdds(t *testing.T) {
+	// A flush draw with one card to come: 20% against a pot-sized bet.
+	d := Decision{Pot: 200, Call: 100, Stack: 1000, CardsToCome: 1}
+	advice, err := Advise(20, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(advice.CallEV+40) > 1e-9 || math.Abs(advice.ImpliedNeeded-200) > 1e-9 {
+		t.Fatalf("unexpected advice %+v", advice)
+	}
+	if advice.Recommendation != RecommendCallImplied {
+		t.Fatalf("expected a call on implied odds, got %+v", advice)
+	}
+
+	d.Stack = 250
+	if advice, _ := Advise(20, d); advice.Recommendation != RecommendFold {
+		t.Fatalf("expected a fold without enough stack behind, got %+v", advice)
+	}
+	d.Stack, d.CardsToCome = 1000, 0
+	if advice, _ := Advise(20, d); advice.Recommendation != RecommendFold {
+		t.Fatalf("expected a fold on the river, got %+v", advice)
+	}
+}
+
+func TestAdviseAllIn(t *testing.T) {
+	// Calling 300 off a 100 stack only risks 100 and returns 200 of the bet.
+	advice, err := Advise(40, Decision{Pot: 400, Call: 300, Stack: 100, CardsToCome: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.Call != 100 || math.Abs(advice.RequiredEquity-100.0/3) > 1e-9 || advice.Recommendation != RecommendCall {
+		t.Fatalf("unexpected advice %+v", advice)
+	}
+
+	for _, d := range []Decision{{Pot: 100}, {Pot: 10, Call: 20}, {Pot: 100, Call: 20, Stack: -1}} {
+		if _, err := Advise(50, d); err == nil {
+			t.Fatalf("expected error for %+v", d)
+		}
+	}
+	if _, err := Advise(120, Decision{Pot: 100, Call: 20}); err == nil {
+		t.Fatal("expected error for equity above 100")
+	}
+}
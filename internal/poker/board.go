@@ -0,0 +1,205 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// SuitTexture describes how the suits of a board are spread.
+type SuitTexture int
+
+const (
+	// Rainbow boards have no two cards of the same suit.
+	Rainbow SuitTexture = iota
+	// TwoTone boards hold at most two cards of any suit, so flush draws are
+	// possible but no flush yet.
+	TwoTone
+	// FlushBoard boards hold three or more cards of one suit next to cards
+	// of other suits.
+	FlushBoard
+	// Monotone boards are a single suit.
+	Monotone
+)
+
+// Connectedness describes how many board ranks fit into one straight.
+type Connectedness int
+
+const (
+	// Disconnected boards have no two ranks within a straight of each other.
+	Disconnected Connectedness = iota
+	// Gapped boards have two ranks within a straight: draws are possible while
+	// cards are to come.
+	Gapped
+	// Connected boards have three or more ranks within a straight, so a
+	// straight can already be made.
+	Connected
+)
+
+// BoardTexture classifies a flop, turn or river.
+type BoardTexture struct {
+	Game  Game
+	Board []Card
+	// Pairing is the category the board makes on its own: HighCard, OnePair,
+	// TwoPair, ThreeOfAKind, FullHouse or FourOfAKind.
+	Pairing       HandCategory
+	Suits         SuitTexture
+	Connectedness Connectedness
+	// FlushDraw and StraightDraw report whether a player can hold a draw to a
+	// flush or a straight with cards still to come.
+	FlushDraw    bool
+	StraightDraw bool
+	// Straights lists the high card of every straight a player can make on
+	// the board, highest first.
+	Straights []Rank
+	// Nuts is the best hand any holding makes on the board, and NutCombos the
+	// two-card combinations that make it. In Omaha these are the two hole
+	// cards a hand has to play.
+	Nuts      HandRank
+	NutCombos []Combo
+	// Made counts the two-card combinations per category of the hand they
+	// make, out of Holdings combinations in total. Comparing the strong
+	// categories shows which ranges the board favours.
+	Made     [StraightFlush + 1]int
+	Holdings int
+}
+
+// Paired reports whether the board holds at least two cards of a rank.
+func (t BoardTexture) Paired() bool {
+	return t.Pairing != HighCard
+}
+
+// Share returns the percentage of holdings making a hand of the category.
+func (t BoardTexture) Share(c HandCategory) float64 {
+	return percentage(t.Made[c], t.Holdings)
+}
+
+// NutShare returns the percentage of holdings making the nuts.
+func (t BoardTexture) NutShare() float64 {
+	return percentage(len(t.NutCombos), t.Holdings)
+}
+
+// AnalyzeBoard classifies the board's pairing, suits and connectedness, lists
+// the possible straights and finds the nuts together with how often each
+// category of made hand is held. Every two-card combination left in the deck
+// counts as one holding.
+func AnalyzeBoard(g Game, board []Card) (BoardTexture, error) {
+	if len(board) < 3 || len(board) > 5 {
+		return BoardTexture{}, fmt.Errorf("board must have 3 to 5 cards, got %d", len(board))
+	}
+	for _, c := range board {
+		if !g.HasCard(c) {
+			return BoardTexture{}, fmt.Errorf("card %s is not in the %s deck", c, g)
+		}
+	}
+	set := NewCardSet(board...)
+	if set.Len() != len(board) {
+		return BoardTexture{}, errors.New("duplicate cards provided")
+	}
+
+	t := BoardTexture{
+		Game:    g,
+		Board:   append([]Card(nil), board...),
+		Pairing: g.rules().evaluate(set).Category,
+	}
+	toCome := len(board) < 5
+
+	most := 0
+	for suit := Clubs; suit <= Spades; suit++ {
+		n := bits.OnesCount16(set.suitMask(suit))
+		most = max(most, n)
+		if n >= 2 && toCome {
+			t.FlushDraw = true
+		}
+	}
+	switch {
+	case most == len(board):
+		t.Suits = Monotone
+	case most >= 3:
+		t.Suits = FlushBoard
+	case most == 2:
+		t.Suits = TwoTone
+	}
+
+	ranks := set.suitMask(Clubs) | set.suitMask(Diamonds) | set.suitMask(Hearts) | set.suitMask(Spades)
+	switch window := straightWindow(ranks, g.lowestRank()); {
+	case window >= 3:
+		t.Connectedness = Connected
+	case window == 2:
+		t.Connectedness = Gapped
+	}
+	t.StraightDraw = toCome && t.Connectedness != Disconnected
+	t.Straights = boardStraights(g, board)
+
+	hasNuts := false
+	for _, combo := range anyTwo(g.BuildDeck(board)) {
+		hand := g.evaluate(combo.set(), set)
+		t.Made[hand.Category]++
+		t.Holdings++
+		switch cmp := g.Compare(hand, t.Nuts); {
+		case !hasNuts || cmp > 0:
+			t.Nuts, t.NutCombos, hasNuts = hand, []Combo{combo}, true
+		case cmp == 0:
+			t.NutCombos = append(t.NutCombos, combo)
+		}
+	}
+	return t, nil
+}
+
+// straightWindow returns the largest number of ranks of the mask that fit in
+// one straight, counting the ace both high and low.
+func straightWindow(ranks uint16, lowest Rank) int {
+	best := 0
+	for high := lowest + 4; high <= Ace; high++ {
+		window := uint16(1<<5-1) << (high - 4)
+		best = max(best, bits.OnesCount16(ranks&window))
+	}
+	wheel := uint16(1)<<Ace | uint16(1<<4-1)<<lowest
+	return max(best, bits.OnesCount16(ranks&wheel))
+}
+
+// boardStraights finds the high card of every straight a player can make by
+// adding up to two ranks to the board, or exactly two to three board cards in
+// Omaha, relying on straightHighRank to pick the straight such a hand plays.
+func boardStraights(g Game, board []Card) []Rank {
+	var bases []uint16
+	if g.IsOmaha() {
+		for a := 0; a < len(board)-2; a++ {
+			for b := a + 1; b < len(board)-1; b++ {
+				for c := b + 1; c < len(board); c++ {
+					bases = append(bases, 1<<board[a].Rank|1<<board[b].Rank|1<<board[c].Rank)
+				}
+			}
+		}
+	} else {
+		var ranks uint16
+		for _, c := range board {
+			ranks |= 1 << c.Rank
+		}
+		bases = append(bases, ranks)
+	}
+
+	found := make(map[Rank]bool)
+	lowest := g.lowestRank()
+	for _, base := range bases {
+		for r1 := lowest; r1 <= Ace; r1++ {
+			for r2 := r1; r2 <= Ace; r2++ {
+				mask := base | 1<<r1 | 1<<r2
+				if g.IsOmaha() && (r1 == r2 || bits.OnesCount16(mask) != 5) {
+					continue
+				}
+				if high, ok := straightHighRank(int(mask), lowest); ok {
+					found[high] = true
+				}
+			}
+		}
+	}
+
+	straights := make([]Rank, 0, len(found))
+	for high := range found {
+		straights = append(straights, high)
+	}
+	sort.Slice(straights, func(i, j int) bool { return straights[i] > straights[j] })
+	return straights
+}
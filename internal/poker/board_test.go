@@ -0,0 +1,143 @@
+package poker
+
+import "testing"
+
+func TestAnalyzeBoardBroadway(t *testing.T) {
+	tex, err := AnalyzeBoard(GameHoldem, cards("Qh", "Jh", "Td"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tex.Paired() || tex.Suits != TwoTone || tex.Connectedness != Connected || !tex.FlushDraw || !tex.StraightDraw {
+		t.Fatalf("unexpected texture %+v", tex)
+	}
+	want := []Rank{Ace, King, Queen}
+	if len(tex.Straights) != len(want) {
+		t.Fatalf("expected straights %v, got %v", want, tex.Straights)
+	}
+	for i, high := range want {
+		if tex.Straights[i] != high {
+			t.Fatalf("expected straights %v, got %v", want, tex.Straights)
+		}
+	}
+	if tex.Nuts.Category != Straight || tex.Nuts.Values[0] != Ace || len(tex.NutCombos) != 16 {
+		t.Fatalf("expected AK as the nuts, got %+v %v", tex.Nuts, tex.NutCombos)
+	}
+	if tex.Holdings != 1176 {
+		t.Fatalf("expected 1176 holdings, got %d", tex.Holdings)
+	}
+	total := 0
+	for _, n := range tex.Made {
+		total += n
+	}
+	if total != tex.Holdings {
+		t.Fatalf("expected every holding to be counted once, got %d", total)
+	}
+	// AK, K9 and 98 in 16 combos each; no straight flush without the Th.
+	if tex.Made[Straight] != 48 || tex.Made[StraightFlush] != 0 {
+		t.Fatalf("unexpected straight counts %d and %d", tex.Made[Straight], tex.Made[StraightFlush])
+	}
+}
+
+func TestAnalyzeBoardTextures(t *testing.T) {
+	cases := []struct {
+		board     []Card
+		pairing   HandCategory
+		suits     SuitTexture
+		connect   Connectedness
+		flushDraw bool
+	}{
+		{cards("Kc", "7d", "2s"), HighCard, Rainbow, Disconnected, false},
+		{cards("9h", "5h", "2h"), HighCard, Monotone, Gapped, true},
+		{cards("9h", "5h", "2h", "Kc"), HighCard, FlushBoard, Gapped, true},
+		{cards("8c", "8d", "Kc", "Ks"), TwoPair, TwoTone, Disconnected, true},
+		{cards("Ad", "Kd", "5d", "2c", "7d"), HighCard, FlushBoard, Connected, false},
+		{cards("Tc", "Td", "Th"), ThreeOfAKind, Rainbow, Disconnected, false},
+	}
+	for _, tc := range cases {
+		tex, err := AnalyzeBoard(GameHoldem, tc.board)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tex.Pairing != tc.pairing || tex.Suits != tc.suits || tex.Connectedness != tc.connect || tex.FlushDraw != tc.flushDraw {
+			t.Fatalf("%v: unexpected texture %v %v %v, flush draw %v", tc.board, tex.Pairing, tex.Suits, tex.Connectedness, tex.FlushDraw)
+		}
+	}
+
+	river, err := AnalyzeBoard(GameHoldem, cards("Kc", "Qd", "Jh", "4s", "2c"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if river.FlushDraw || river.StraightDraw {
+		t.Fatalf("expected no draws on the river, got %+v", river)
+	}
+}
+
+func TestAnalyzeBoardNuts(t *testing.T) {
+	tex, err := AnalyzeBoard(GameHoldem, cards("Kc", "7d", "2s"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tex.Nuts.Category != ThreeOfAKind || tex.Nuts.Values[0] != King || len(tex.NutCombos) != 3 {
+		t.Fatalf("expected top set as the nuts, got %+v %v", tex.Nuts, tex.NutCombos)
+	}
+	if len(tex.Straights) != 0 {
+		t.Fatalf("expected no straights, got %v", tex.Straights)
+	}
+}
+
+func TestAnalyzeBoardOmaha(t *testing.T) {
+	// Four to a straight: Omaha hands play exactly three of them, so the
+	// wheel and the six-high straight need two hole cards.
+	tex, err := AnalyzeBoard(GameOmaha4, cards("5c", "4d", "3h", "2s"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Rank{Seven, Six, Five}
+	if len(tex.Straights) != len(want) {
+		t.Fatalf("expected straights %v, got %v", want, tex.Straights)
+	}
+	for i, high := range want {
+		if tex.Straights[i] != high {
+			t.Fatalf("expected straights %v, got %v", want, tex.Straights)
+		}
+	}
+	if tex.Nuts.Category != Straight || tex.Nuts.Values[0] != Seven {
+		t.Fatalf("expected the seven-high straight as the nuts, got %+v", tex.Nuts)
+	}
+
+	holdem, err := AnalyzeBoard(GameHoldem, cards("5c", "4d", "3h", "2s"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if holdem.Straights[len(holdem.Straights)-1] != Five {
+		t.Fatalf("expected the wheel with a single ace in Hold'em, got %v", holdem.Straights)
+	}
+}
+
+func TestAnalyzeBoardShortDeck(t *testing.T) {
+	tex, err := AnalyzeBoard(GameShortDeck, cards("Ac", "7d", "8s"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tex.Connectedness != Connected || tex.Holdings != 528 {
+		t.Fatalf("unexpected texture %+v", tex)
+	}
+	if tex.Straights[len(tex.Straights)-1] != Nine {
+		t.Fatalf("expected the A-6-7-8-9 straight, got %v", tex.Straights)
+	}
+}
+
+func TestAnalyzeBoardErrors(t *testing.T) {
+	for _, board := range [][]Card{
+		cards("Ah", "Kh"),
+		cards("Ah", "Kh", "Qh", "Jh", "Th", "9h"),
+		cards("Ah", "Ah", "Kd"),
+	} {
+		if _, err := AnalyzeBoard(GameHoldem, board); err == nil {
+			t.Fatalf("expected error for %v", board)
+		}
+	}
+	if _, err := AnalyzeBoard(GameShortDeck, cards("Ah", "2h", "Kd")); err == nil {
+		t.Fatal("expected error for a stripped card in Short Deck")
+	}
+}
@@ -81,6 +81,10 @@ var stringToRank = map[string]Rank{
 	"A":  Ace,
 }
 
+func (r Rank) String() string {
+	return rankToString[r]
+}
+
 // Card represents a single playing card.
 type Card struct {
 	Rank Rank
@@ -0,0 +1,56 @@
+package poker
+
+import "math/bits"
+
+// CardSet is a set of cards packed into 64 bits. Every suit owns a 16-bit lane
+// in which bit r marks the presence of rank r, so a lane doubles as the rank
+// mask of that suit.
+type CardSet uint64
+
+const rankLaneMask = 1<<13 - 1
+
+// NewCardSet builds a set from the given cards.
+func NewCardSet(cards ...Card) CardSet {
+	var s CardSet
+	for _, c := range cards {
+		s |= cardBit(c)
+	}
+	return s
+}
+
+func cardBit(c Card) CardSet {
+	return 1 << (uint(c.Suit)*16 + uint(c.Rank))
+}
+
+// Add returns the set extended with the card.
+func (s CardSet) Add(c Card) CardSet {
+	return s | cardBit(c)
+}
+
+// Contains reports whether the card belongs to the set.
+func (s CardSet) Contains(c Card) bool {
+	return s&cardBit(c) != 0
+}
+
+// Len returns the number of cards in the set.
+func (s CardSet) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Cards lists the set members ordered by suit and rank.
+func (s CardSet) Cards() []Card {
+	cards := make([]Card, 0, s.Len())
+	for suit := Clubs; suit <= Spades; suit++ {
+		lane := s.suitMask(suit)
+		for lane != 0 {
+			rank := Rank(bits.TrailingZeros16(lane))
+			cards = append(cards, Card{Rank: rank, Suit: suit})
+			lane &= lane - 1
+		}
+	}
+	return cards
+}
+
+func (s CardSet) suitMask(suit Suit) uint16 {
+	return uint16(s>>(uint(suit)*16)) & rankLaneMask
+}
@@ -0,0 +1,29 @@
+package poker
+
+import "testing"
+
+func TestCardSet(t *testing.T) {
+	set := NewCardSet(MustParseCard("Ah"), MustParseCard("2c"), MustParseCard("Ts"))
+	if set.Len() != 3 {
+		t.Fatalf("expected 3 cards, got %d", set.Len())
+	}
+	if !set.Contains(MustParseCard("Ah")) || set.Contains(MustParseCard("Ad")) {
+		t.Fatalf("unexpected membership in %b", set)
+	}
+
+	set = set.Add(MustParseCard("Ah")).Add(MustParseCard("Kd"))
+	got := set.Cards()
+	want := []Card{MustParseCard("2c"), MustParseCard("Kd"), MustParseCard("Ah"), MustParseCard("Ts")}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	if NewCardSet(AllCards()...).Len() != 52 {
+		t.Fatal("full deck must contain 52 distinct cards")
+	}
+}
@@ -2,7 +2,7 @@ package poker
 
 import (
 	"errors"
-	"sort"
+	"math/bits"
 )
 
 // HandCategory enumerates poker hand categories ordered by strength.
@@ -26,12 +26,17 @@ type HandRank struct {
 	Values   [5]Rank
 }
 
-// Compare returns 1 if h > other, -1 if h < other, 0 otherwise.
+// Compare returns 1 if h > other, -1 if h < other, 0 otherwise, using the
+// standard category order. Use Game.Compare for variants that reorder it.
 func (h HandRank) Compare(other HandRank) int {
-	if h.Category > other.Category {
-		return 1
-	}
-	if h.Category < other.Category {
+	return h.compare(other, standardRules)
+}
+
+func (h HandRank) compare(other HandRank, r *rules) int {
+	if a, b := r.strength(h.Category), r.strength(other.Category); a != b {
+		if a > b {
+			return 1
+		}
 		return -1
 	}
 
@@ -48,201 +53,170 @@ func (h HandRank) Compare(other HandRank) int {
 
 // EvaluateBestHand finds the best five-card hand from the provided cards.
 func EvaluateBestHand(cards []Card) (HandRank, error) {
-	n := len(cards)
-	if n < 5 {
+	if len(cards) < 5 {
 		return HandRank{}, errors.New("at least five cards required")
 	}
+	return EvaluateCardSet(NewCardSet(cards...)), nil
+}
 
-	best := HandRank{}
-	hasBest := false
-
-	for i := 0; i < n-4; i++ {
-		for j := i + 1; j < n-3; j++ {
-			for k := j + 1; k < n-2; k++ {
-				for l := k + 1; l < n-1; l++ {
-					for m := l + 1; m < n; m++ {
-						hand := []Card{cards[i], cards[j], cards[k], cards[l], cards[m]}
-						eval := evaluateFive(hand)
-						if !hasBest || eval.Compare(best) > 0 {
-							best = eval
-							hasBest = true
-						}
-					}
-				}
-			}
+// rules captures how a deck variant ranks hands.
+type rules struct {
+	// straights holds, per 13-bit rank mask, the high rank of the best
+	// straight plus one, zero when the mask contains no straight.
+	straights [1 << 13]uint8
+	// flushBeatsFullHouse swaps the two categories, as in Short Deck where
+	// flushes are rarer than full houses.
+	flushBeatsFullHouse bool
+}
+
+func newRules(lowest Rank, flushBeatsFullHouse bool) *rules {
+	r := &rules{flushBeatsFullHouse: flushBeatsFullHouse}
+	for mask := range r.straights {
+		if high, ok := straightHighRank(mask, lowest); ok {
+			r.straights[mask] = uint8(high) + 1
 		}
 	}
-
-	return best, nil
+	return r
 }
 
-func evaluateFive(cards []Card) HandRank {
-	suitCounts := make(map[Suit]int, 4)
-	rankCounts := make(map[Rank]int, len(cards))
-	rankMask := 0
-
-	for _, c := range cards {
-		suitCounts[c.Suit]++
-		rankCounts[c.Rank]++
-		rankMask |= 1 << int(c.Rank)
+// strength orders categories under the rules.
+func (r *rules) strength(c HandCategory) int {
+	if r.flushBeatsFullHouse {
+		switch c {
+		case Flush:
+			return int(FullHouse)
+		case FullHouse:
+			return int(Flush)
+		}
 	}
+	return int(c)
+}
 
-	allRanksDesc := sortedRanksByCount(rankCounts)
+var (
+	standardRules  = newRules(Two, false)
+	shortDeckRules = newRules(Six, true)
+)
+
+// topRanksTable lists the five highest ranks of a 13-bit rank mask, descending.
+var topRanksTable [1 << 13][5]uint8
 
-	isFlush := false
-	flushSuit := Clubs
-	for suit, count := range suitCounts {
-		if count == 5 {
-			isFlush = true
-			flushSuit = suit
-			break
+func init() {
+	for mask := range topRanksTable {
+		rest := uint16(mask)
+		for i := 0; i < 5 && rest != 0; i++ {
+			r := highestRank(rest)
+			topRanksTable[mask][i] = uint8(r)
+			rest &^= 1 << r
 		}
 	}
+}
 
-	if isFlush {
-		flushRanks := make([]Rank, 0, 5)
-		for _, c := range cards {
-			if c.Suit == flushSuit {
-				flushRanks = append(flushRanks, c.Rank)
-			}
-		}
-		sort.Slice(flushRanks, func(i, j int) bool { return flushRanks[i] > flushRanks[j] })
+// EvaluateCardSet ranks the best five-card hand contained in the set under
+// standard rules. It works on the suit lanes directly and does not allocate.
+func EvaluateCardSet(s CardSet) HandRank {
+	return standardRules.evaluate(s)
+}
 
-		rankMaskFlush := 0
-		for _, r := range flushRanks {
-			rankMaskFlush |= 1 << int(r)
+func (r *rules) evaluate(s CardSet) HandRank {
+	c, d, h, sp := s.suitMask(Clubs), s.suitMask(Diamonds), s.suitMask(Hearts), s.suitMask(Spades)
+	ranks := c | d | h | sp
+
+	var flush uint16
+	hasFlush := false
+	for _, lane := range [4]uint16{c, d, h, sp} {
+		if bits.OnesCount16(lane) < 5 {
+			continue
 		}
-		if high, ok := straightHighRank(rankMaskFlush); ok {
-			return handRankFromSlice(StraightFlush, []Rank{high})
+		if high := r.straights[lane]; high != 0 {
+			return HandRank{Category: StraightFlush, Values: [5]Rank{Rank(high - 1)}}
+		}
+		if !hasFlush || topRanksValue(lane) > topRanksValue(flush) {
+			flush = lane
+			hasFlush = true
 		}
-
-		return handRankFromSlice(Flush, flushRanks)
 	}
 
-	if high, ok := straightHighRank(rankMask); ok {
-		return handRankFromSlice(Straight, []Rank{high})
+	if quads := c & d & h & sp; quads != 0 {
+		quad := highestRank(quads)
+		kicker := highestRank(ranks &^ (1 << quad))
+		return HandRank{Category: FourOfAKind, Values: [5]Rank{quad, kicker}}
 	}
 
-	counts := make([]countRank, 0, len(rankCounts))
-	for rank, count := range rankCounts {
-		counts = append(counts, countRank{Rank: rank, Count: count})
+	if hasFlush && r.flushBeatsFullHouse {
+		return topRanks(Flush, flush, 5)
 	}
-	sort.Slice(counts, func(i, j int) bool {
-		if counts[i].Count == counts[j].Count {
-			return counts[i].Rank > counts[j].Rank
-		}
-		return counts[i].Count > counts[j].Count
-	})
 
-	if counts[0].Count == 4 {
-		fourRank := counts[0].Rank
-		kicker := highestExcluding(allRanksDesc, fourRank)
-		return handRankFromSlice(FourOfAKind, []Rank{fourRank, kicker})
-	}
+	trips := (c & d & h) | (c & d & sp) | (c & h & sp) | (d & h & sp)
+	pairs := (c & d) | (c & h) | (c & sp) | (d & h) | (d & sp) | (h & sp)
 
-	if counts[0].Count == 3 {
-		tripRank := counts[0].Rank
-		if pairRank, ok := highestPairRank(counts[1:]); ok {
-			return handRankFromSlice(FullHouse, []Rank{tripRank, pairRank})
+	if trips != 0 {
+		trip := highestRank(trips)
+		if rest := pairs &^ (1 << trip); rest != 0 {
+			return HandRank{Category: FullHouse, Values: [5]Rank{trip, highestRank(rest)}}
 		}
-
-		kickers := topRanksExcluding(allRanksDesc, []Rank{tripRank}, 2)
-		values := append([]Rank{tripRank}, kickers...)
-		return handRankFromSlice(ThreeOfAKind, values)
 	}
 
-	pairs := collectPairs(counts)
-	if len(pairs) >= 2 {
-		first, second := pairs[0], pairs[1]
-		if second > first {
-			first, second = second, first
-		}
-		kicker := highestExcluding(allRanksDesc, first, second)
-		values := []Rank{first, second, kicker}
-		return handRankFromSlice(TwoPair, values)
+	if hasFlush {
+		return topRanks(Flush, flush, 5)
 	}
 
-	if len(pairs) == 1 {
-		pair := pairs[0]
-		kickers := topRanksExcluding(allRanksDesc, []Rank{pair}, 3)
-		values := append([]Rank{pair}, kickers...)
-		return handRankFromSlice(OnePair, values)
+	if high := r.straights[ranks]; high != 0 {
+		return HandRank{Category: Straight, Values: [5]Rank{Rank(high - 1)}}
 	}
 
-	highCards := topRanksExcluding(allRanksDesc, nil, 5)
-	return handRankFromSlice(HighCard, highCards)
-}
-
-type countRank struct {
-	Rank  Rank
-	Count int
-}
-
-func sortedRanksByCount(rankCounts map[Rank]int) []Rank {
-	ranks := make([]Rank, 0, len(rankCounts))
-	for rank, count := range rankCounts {
-		for i := 0; i < count; i++ {
-			ranks = append(ranks, rank)
-		}
+	if trips != 0 {
+		trip := highestRank(trips)
+		return withLead(ThreeOfAKind, []Rank{trip}, ranks&^(1<<trip), 2)
 	}
-	sort.Slice(ranks, func(i, j int) bool { return ranks[i] > ranks[j] })
-	return ranks
-}
 
-func highestExcluding(ranks []Rank, excludes ...Rank) Rank {
-	excludeSet := make(map[Rank]struct{}, len(excludes))
-	for _, e := range excludes {
-		excludeSet[e] = struct{}{}
-	}
-	for _, r := range ranks {
-		if _, skip := excludeSet[r]; skip {
-			continue
+	if pairs != 0 {
+		high := highestRank(pairs)
+		if rest := pairs &^ (1 << high); rest != 0 {
+			low := highestRank(rest)
+			return withLead(TwoPair, []Rank{high, low}, ranks&^(1<<high|1<<low), 1)
 		}
-		return r
+		return withLead(OnePair, []Rank{high}, ranks&^(1<<high), 3)
 	}
-	return Two
+
+	return topRanks(HighCard, ranks, 5)
 }
 
-func topRanksExcluding(ranks []Rank, excludes []Rank, needed int) []Rank {
-	excludeSet := make(map[Rank]struct{}, len(excludes))
-	for _, e := range excludes {
-		excludeSet[e] = struct{}{}
-	}
+func highestRank(mask uint16) Rank {
+	return Rank(bits.Len16(mask) - 1)
+}
 
-	result := make([]Rank, 0, needed)
-	for _, r := range ranks {
-		if _, skip := excludeSet[r]; skip {
-			continue
-		}
-		result = append(result, r)
-		if len(result) == needed {
-			break
-		}
-	}
-	return result
+// topRanksValue orders rank masks by their five highest ranks.
+func topRanksValue(mask uint16) uint32 {
+	top := topRanksTable[mask]
+	return uint32(top[0])<<16 | uint32(top[1])<<12 | uint32(top[2])<<8 | uint32(top[3])<<4 | uint32(top[4])
 }
 
-func highestPairRank(counts []countRank) (Rank, bool) {
-	for _, cr := range counts {
-		if cr.Count >= 2 {
-			return cr.Rank, true
-		}
+func topRanks(category HandCategory, mask uint16, count int) HandRank {
+	h := HandRank{Category: category}
+	top := topRanksTable[mask]
+	for i := 0; i < count; i++ {
+		h.Values[i] = Rank(top[i])
 	}
-	return 0, false
+	return h
 }
 
-func collectPairs(counts []countRank) []Rank {
-	pairs := make([]Rank, 0, len(counts))
-	for _, cr := range counts {
-		if cr.Count >= 2 {
-			pairs = append(pairs, cr.Rank)
-		}
+// withLead fills the leading values (pairs, trips) and completes the hand with
+// the highest kickers taken from the remaining rank mask.
+func withLead(category HandCategory, lead []Rank, kickers uint16, count int) HandRank {
+	h := HandRank{Category: category}
+	n := copy(h.Values[:], lead)
+	top := topRanksTable[kickers]
+	for i := 0; i < count; i++ {
+		h.Values[n+i] = Rank(top[i])
 	}
-	return pairs
+	return h
 }
 
-func straightHighRank(mask int) (Rank, bool) {
-	for high := Ace; high >= Five; high-- {
+// straightHighRank finds the best straight in the rank mask. lowest is the
+// lowest rank in the deck: the ace also plays below it, so the lowest straight
+// is A-2-3-4-5 in a standard deck and A-6-7-8-9 in Short Deck.
+func straightHighRank(mask int, lowest Rank) (Rank, bool) {
+	for high := Ace; high >= lowest+4; high-- {
 		needed := 0
 		valid := true
 		for offset := 0; offset < 5; offset++ {
@@ -258,9 +232,12 @@ func straightHighRank(mask int) (Rank, bool) {
 		}
 	}
 
-	wheelMask := (1 << int(Ace)) | (1 << int(Two)) | (1 << int(Three)) | (1 << int(Four)) | (1 << int(Five))
+	wheelMask := 1 << int(Ace)
+	for r := lowest; r <= lowest+3; r++ {
+		wheelMask |= 1 << int(r)
+	}
 	if mask&wheelMask == wheelMask {
-		return Five, true
+		return lowest + 3, true
 	}
 
 	return 0, false
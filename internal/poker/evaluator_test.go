@@ -1,6 +1,9 @@
 package poker
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+)
 
 func TestEvaluateBestHandCategories(t *testing.T) {
 	tests := []struct {
@@ -105,3 +108,103 @@ func TestHandRankCompare(t *testing.T) {
 		t.Fatalf("expected a > b based on kicker")
 	}
 }
+
+func TestEvaluateBestHandMatchesReference(t *testing.T) {
+	boards := [][]Card{
+		cards("2h", "5h", "9h", "Jh", "Kh"),
+		cards("7c", "7d", "7h", "2s", "2d"),
+		cards("5c", "6d", "7h", "8s", "Ad"),
+		cards("Th", "Jh", "Qh", "3h", "3d"),
+		cards("Ac", "Ad", "Kc", "Kd", "4s"),
+	}
+
+	sampled := 20
+	if testing.Short() {
+		sampled = 3
+	}
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < sampled; i++ {
+		deck := BuildDeck(nil)
+		boards = append(boards, DrawCards(&deck, 5, rng))
+	}
+
+	for _, board := range boards {
+		deck := BuildDeck(board)
+		for i := 0; i < len(deck)-1; i++ {
+			for j := i + 1; j < len(deck); j++ {
+				hand := append([]Card{deck[i], deck[j]}, board...)
+				got, err := EvaluateBestHand(hand)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if want := referenceBestHand(hand); got != want {
+					t.Fatalf("%v: expected %+v, got %+v", hand, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestEvaluateCardSetAllFiveCardHands(t *testing.T) {
+	if testing.Short() {
+		t.Skip("exhaustive five-card check skipped in short mode")
+	}
+
+	deck := AllCards()
+	hand := make([]Card, 5)
+	for a := 0; a < len(deck); a++ {
+		for b := a + 1; b < len(deck); b++ {
+			for c := b + 1; c < len(deck); c++ {
+				for d := c + 1; d < len(deck); d++ {
+					for e := d + 1; e < len(deck); e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = deck[a], deck[b], deck[c], deck[d], deck[e]
+						got := EvaluateCardSet(NewCardSet(hand...))
+						if want := referenceEvaluateFive(hand); got != want {
+							t.Fatalf("%v: expected %+v, got %+v", hand, want, got)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func benchmarkHands(n int) [][]Card {
+	rng := rand.New(rand.NewSource(5))
+	hands := make([][]Card, n)
+	for i := range hands {
+		deck := BuildDeck(nil)
+		hands[i] = DrawCards(&deck, 7, rng)
+	}
+	return hands
+}
+
+func BenchmarkEvaluateBestHand(b *testing.B) {
+	hands := benchmarkHands(1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := EvaluateBestHand(hands[i%len(hands)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEvaluateCardSet(b *testing.B) {
+	hands := benchmarkHands(1024)
+	sets := make([]CardSet, len(hands))
+	for i, h := range hands {
+		sets[i] = NewCardSet(h...)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EvaluateCardSet(sets[i%len(sets)])
+	}
+}
+
+func BenchmarkReferenceBestHand(b *testing.B) {
+	hands := benchmarkHands(1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceBestHand(hands[i%len(hands)])
+	}
+}
@@ -0,0 +1,202 @@
+package poker
+
+import "context"
+
+// enumerateCheckInterval is the number of showdowns recorded between checks
+// of the context during enumeration.
+const enumerateCheckInterval = 1 << 14
+
+// canEnumerate reports whether the table is small enough to be solved exactly.
+// Only fixed, range-constrained and uniformly random opponents can be
+// enumerated: Omaha styles short of any two cards are sampled by rejection
+// and have no closed form.
+func (t *table) canEnumerate(exactLimit int) bool {
+	if exactLimit < 0 {
+		return false
+	}
+	for _, style := range t.styles {
+		if style.Percent() < 100 {
+			return false
+		}
+	}
+	limit := exactLimit
+	if limit == 0 {
+		limit = DefaultExactLimit
+	}
+
+	outcomes := 1
+	unseen := len(t.deck)
+	if len(t.heroCombos) > 0 {
+		outcomes = len(t.heroCombos)
+		unseen -= 2
+	}
+	for _, combos := range t.ranged {
+		outcomes = boundedProduct(outcomes, len(combos), limit)
+		unseen -= 2
+	}
+	outcomes = boundedProduct(outcomes, binomial(unseen, t.boardNeeded), limit)
+	unseen -= t.boardNeeded
+	hole := t.game.HoleCards()
+	for range t.styles {
+		outcomes = boundedProduct(outcomes, binomial(unseen, hole), limit)
+		unseen -= hole
+	}
+	return outcomes <= limit
+}
+
+// boundedProduct multiplies a and b, saturating just above limit so that large
+// enumeration spaces cannot overflow.
+func boundedProduct(a, b, limit int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a > limit/b {
+		return limit + 1
+	}
+	return a * b
+}
+
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+	}
+	return result
+}
+
+// enumerate walks every consistent assignment of hero combos, range combos,
+// board runouts and random hole cards, weighting each showdown equally. It
+// stops with the context error once ctx is done: a partial walk covers the
+// outcomes in order and is not a fair sample.
+func (t *table) enumerate(ctx context.Context) (tally, error) {
+	e := enumerator{
+		ctx:       ctx,
+		table:     t,
+		hero:      t.hero,
+		heroCombo: -1,
+		board:     t.board,
+		ranged:    make([]CardSet, len(t.ranged)),
+		t:         newTally(t.seats, len(t.heroCombos)),
+	}
+	if len(t.heroCombos) == 0 {
+		e.assignRanged(0)
+		return e.t, e.err
+	}
+
+	for i, combo := range t.heroCombos {
+		e.heroCombo = i
+		e.hero = combo.set()
+		e.used = e.hero
+		e.assignRanged(0)
+	}
+	return e.t, e.err
+}
+
+type enumerator struct {
+	ctx context.Context
+	// err is the context error that stopped the walk.
+	err error
+	*table
+	// used holds the deck cards dealt in the current branch.
+	used      CardSet
+	hero      CardSet
+	heroCombo int
+	board     CardSet
+	ranged    []CardSet
+	heroRank  HandRank
+	t         tally
+}
+
+// assignRanged picks a combo for the next opponent with known cards or a range.
+func (e *enumerator) assignRanged(seat int) {
+	if e.err != nil {
+		return
+	}
+	if seat == len(e.ranged) {
+		e.runouts(0, e.boardNeeded)
+		return
+	}
+
+	for _, combo := range e.table.ranged[seat] {
+		hand := combo.set()
+		if hand&e.used != 0 {
+			continue
+		}
+		e.ranged[seat] = hand
+		e.used |= hand
+		e.assignRanged(seat + 1)
+		e.used &^= hand
+	}
+}
+
+func (e *enumerator) runouts(start, needed int) {
+	if e.err != nil {
+		return
+	}
+	if needed == 0 {
+		e.heroRank = e.game.evaluate(e.hero, e.board)
+		var v verdict
+		for i, hand := range e.fixed {
+			v = showdown(e.game, e.heroRank, e.game.evaluate(hand, e.board), e.fixedSeats[i], v)
+		}
+		for i, hand := range e.ranged {
+			v = showdown(e.game, e.heroRank, e.game.evaluate(hand, e.board), e.rangedSeats[i], v)
+		}
+		e.assignRandom(0, v)
+		return
+	}
+
+	for i := start; i <= len(e.deck)-needed; i++ {
+		card := e.deck[i]
+		if e.used.Contains(card) {
+			continue
+		}
+		board := e.board
+		e.used = e.used.Add(card)
+		e.board = board.Add(card)
+		e.runouts(i+1, needed-1)
+		e.board = board
+		e.used &^= cardBit(card)
+	}
+}
+
+// assignRandom assigns a hand to the next uniformly random opponent.
+func (e *enumerator) assignRandom(seat int, current verdict) {
+	if e.err != nil {
+		return
+	}
+	if seat == len(e.styles) {
+		e.t.record(current, e.heroRank, e.heroCombo)
+		if e.t.total()%enumerateCheckInterval == 0 {
+			e.err = e.ctx.Err()
+		}
+		return
+	}
+	e.chooseHole(seat, 0, e.game.HoleCards(), 0, current)
+}
+
+// chooseHole picks the remaining hole cards of a random seat from the deck in
+// increasing index order, so every hand is visited exactly once.
+func (e *enumerator) chooseHole(seat, start, needed int, hand CardSet, current verdict) {
+	if needed == 0 {
+		next := showdown(e.game, e.heroRank, e.game.evaluate(hand, e.board), e.styleSeats[seat], current)
+		e.used |= hand
+		e.assignRandom(seat+1, next)
+		e.used &^= hand
+		return
+	}
+
+	for i := start; i <= len(e.deck)-needed; i++ {
+		card := e.deck[i]
+		if e.used.Contains(card) {
+			continue
+		}
+		e.chooseHole(seat, i+1, needed-1, hand.Add(card), current)
+	}
+}
@@ -0,0 +1,175 @@
+package poker
+
+import "fmt"
+
+// Game selects the poker variant being played.
+type Game int
+
+const (
+	GameHoldem Game = iota
+	// GameOmaha4 is Pot Limit Omaha with four hole cards.
+	GameOmaha4
+	// GameOmaha5 is Pot Limit Omaha with five hole cards.
+	GameOmaha5
+	// GameShortDeck is Hold'em with a 36-card deck (sixes through aces) where
+	// a flush beats a full house and A-6-7-8-9 is the lowest straight.
+	GameShortDeck
+)
+
+// HoleCards returns the number of private cards dealt to each player.
+func (g Game) HoleCards() int {
+	switch g {
+	case GameOmaha4:
+		return 4
+	case GameOmaha5:
+		return 5
+	default:
+		return 2
+	}
+}
+
+// IsOmaha reports whether hands must use exactly two hole and three board cards.
+func (g Game) IsOmaha() bool {
+	return g == GameOmaha4 || g == GameOmaha5
+}
+
+func (g Game) String() string {
+	switch g {
+	case GameOmaha4:
+		return "PLO4"
+	case GameOmaha5:
+		return "PLO5"
+	case GameShortDeck:
+		return "Short Deck"
+	default:
+		return "Hold'em"
+	}
+}
+
+// lowestRank returns the lowest rank left in the game's deck.
+func (g Game) lowestRank() Rank {
+	if g == GameShortDeck {
+		return Six
+	}
+	return Two
+}
+
+// DeckSize returns the number of cards in the game's deck.
+func (g Game) DeckSize() int {
+	return 4 * int(Ace-g.lowestRank()+1)
+}
+
+// HasCard reports whether the card is part of the game's deck.
+func (g Game) HasCard(c Card) bool {
+	return c.Rank >= g.lowestRank()
+}
+
+// AllCards returns every card of the game's deck.
+func (g Game) AllCards() []Card {
+	cards := make([]Card, 0, g.DeckSize())
+	for _, c := range AllCards() {
+		if g.HasCard(c) {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// BuildDeck returns the game's deck excluding the specified cards.
+func (g Game) BuildDeck(excluded []Card) []Card {
+	dead := NewCardSet(excluded...) | g.excluded()
+	deck := make([]Card, 0, g.DeckSize())
+	for _, c := range AllCards() {
+		if !dead.Contains(c) {
+			deck = append(deck, c)
+		}
+	}
+	return deck
+}
+
+// excluded returns the standard cards stripped from the game's deck.
+func (g Game) excluded() CardSet {
+	var s CardSet
+	for suit := Clubs; suit <= Spades; suit++ {
+		s |= CardSet(1<<g.lowestRank()-1) << (16 * uint(suit))
+	}
+	return s
+}
+
+func (g Game) rules() *rules {
+	if g == GameShortDeck {
+		return shortDeckRules
+	}
+	return standardRules
+}
+
+// Compare orders two hands under the game's rules, returning 1 if a beats b,
+// -1 if b beats a and 0 on a tie.
+func (g Game) Compare(a, b HandRank) int {
+	return a.compare(b, g.rules())
+}
+
+// EvaluateHand ranks the best hand a player can make under the rules of the
+// game: any five of hole and board cards in Hold'em and Short Deck, exactly
+// two hole and three board cards in Omaha.
+func EvaluateHand(g Game, hole, board []Card) (HandRank, error) {
+	if len(hole) != g.HoleCards() {
+		return HandRank{}, fmt.Errorf("%s requires %d hole cards, got %d", g, g.HoleCards(), len(hole))
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return HandRank{}, fmt.Errorf("board must have 3 to 5 cards, got %d", len(board))
+	}
+	for _, c := range append(append([]Card(nil), hole...), board...) {
+		if !g.HasCard(c) {
+			return HandRank{}, fmt.Errorf("card %s is not in the %s deck", c, g)
+		}
+	}
+	return g.evaluate(NewCardSet(hole...), NewCardSet(board...)), nil
+}
+
+func (g Game) evaluate(hole, board CardSet) HandRank {
+	if g.IsOmaha() {
+		return evaluateOmaha(hole, board)
+	}
+	return g.rules().evaluate(hole | board)
+}
+
+// evaluateOmaha tries every pair of hole cards with every three board cards.
+func evaluateOmaha(hole, board CardSet) HandRank {
+	var holeCards, boardCards [5]CardSet
+	nh := splitCards(hole, holeCards[:])
+	nb := splitCards(board, boardCards[:])
+
+	var best HandRank
+	hasBest := false
+	for i := 0; i < nh-1; i++ {
+		for j := i + 1; j < nh; j++ {
+			pair := holeCards[i] | holeCards[j]
+			for a := 0; a < nb-2; a++ {
+				for b := a + 1; b < nb-1; b++ {
+					for c := b + 1; c < nb; c++ {
+						rank := EvaluateCardSet(pair | boardCards[a] | boardCards[b] | boardCards[c])
+						if !hasBest || rank.Compare(best) > 0 {
+							best = rank
+							hasBest = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// splitCards writes the single-card subsets of s into out and returns how
+// many were written.
+func splitCards(s CardSet, out []CardSet) int {
+	n := 0
+	for s != 0 && n < len(out) {
+		low := s & -s
+		out[n] = low
+		s &^= low
+		n++
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package poker
+
+import "testing"
+
+func TestEvaluateHandOmahaUsesTwoHoleCards(t *testing.T) {
+	// Four hearts in hand and one on board is no flush in Omaha.
+	hole := cards("Ah", "Kh", "Qh", "Jh")
+	board := cards("2h", "7c", "8d", "9s", "3c")
+
+	rank, err := EvaluateHand(GameOmaha4, hole, board)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rank.Category != HighCard {
+		t.Fatalf("expected high card, got %v", rank.Category)
+	}
+
+	holdem, err := EvaluateHand(GameHoldem, cards("Ah", "Kh"), cards("Qh", "Jh", "2h", "7c", "8d"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if holdem.Category != Flush {
+		t.Fatalf("expected flush in hold'em, got %v", holdem.Category)
+	}
+}
+
+func TestEvaluateHandOmahaBoardQuads(t *testing.T) {
+	// A board with four of a kind only plays as trips plus a kicker pair in Omaha.
+	rank, err := EvaluateHand(GameOmaha5, cards("Kh", "Kd", "2c", "3d", "4s"), cards("9c", "9d", "9h", "9s", "Ac"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rank.Category != FullHouse || rank.Values[0] != Nine || rank.Values[1] != King {
+		t.Fatalf("expected nines full of kings, got %+v", rank)
+	}
+}
+
+func TestEvaluateHandValidation(t *testing.T) {
+	if _, err := EvaluateHand(GameOmaha4, cards("Ah", "Kh"), cards("2c", "3c", "4c")); err == nil {
+		t.Fatal("expected error for too few Omaha hole cards")
+	}
+	if _, err := EvaluateHand(GameHoldem, cards("Ah", "Kh"), cards("2c", "3c")); err == nil {
+		t.Fatal("expected error for short board")
+	}
+}
+
+func TestShortDeckDeck(t *testing.T) {
+	deck := GameShortDeck.AllCards()
+	if len(deck) != 36 || GameShortDeck.DeckSize() != 36 {
+		t.Fatalf("expected 36 cards, got %d", len(deck))
+	}
+	for _, c := range deck {
+		if c.Rank < Six {
+			t.Fatalf("unexpected card %s in short deck", c)
+		}
+	}
+	if got := len(GameShortDeck.BuildDeck(cards("Ah", "6c", "2d"))); got != 34 {
+		t.Fatalf("expected 34 cards left, got %d", got)
+	}
+	if len(GameHoldem.AllCards()) != 52 {
+		t.Fatal("expected the full deck for hold'em")
+	}
+}
+
+func TestEvaluateHandShortDeckStraights(t *testing.T) {
+	rank, err := EvaluateHand(GameShortDeck, cards("Ah", "6c"), cards("7d", "8s", "9h", "Kc", "Kd"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rank.Category != Straight || rank.Values[0] != Nine {
+		t.Fatalf("expected nine-high straight, got %+v", rank)
+	}
+
+	if _, err := EvaluateHand(GameShortDeck, cards("Ah", "5c"), cards("7d", "8s", "9h")); err == nil {
+		t.Fatal("expected error for a card outside the short deck")
+	}
+}
+
+func TestShortDeckFlushBeatsFullHouse(t *testing.T) {
+	flush, err := EvaluateHand(GameShortDeck, cards("Ah", "Th"), cards("7h", "8h", "Jh", "Kc", "Kd"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fullHouse, err := EvaluateHand(GameShortDeck, cards("Ks", "7c"), cards("7h", "8h", "Jh", "Kc", "Kd"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flush.Category != Flush || fullHouse.Category != FullHouse {
+		t.Fatalf("unexpected categories %v and %v", flush.Category, fullHouse.Category)
+	}
+	if GameShortDeck.Compare(flush, fullHouse) != 1 {
+		t.Fatal("expected the flush to win in short deck")
+	}
+	if GameHoldem.Compare(flush, fullHouse) != -1 {
+		t.Fatal("expected the full house to win in hold'em")
+	}
+
+}
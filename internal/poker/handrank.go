@@ -0,0 +1,135 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var rankNames = [...]string{"Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King", "Ace"}
+
+var rankPlurals = [...]string{"Twos", "Threes", "Fours", "Fives", "Sixes", "Sevens", "Eights", "Nines", "Tens", "Jacks", "Queens", "Kings", "Aces"}
+
+// Name returns the rank's English name, such as "Queen".
+func (r Rank) Name() string {
+	return rankNames[r]
+}
+
+// Plural returns the rank's English plural, such as "Sixes".
+func (r Rank) Plural() string {
+	return rankPlurals[r]
+}
+
+// String describes the hand in English, such as "Two pair, Aces and Sevens,
+// Queen kicker" or "Flush, King high".
+func (h HandRank) String() string {
+	v := h.Values
+	switch h.Category {
+	case OnePair:
+		return fmt.Sprintf("Pair of %s, %s kicker", v[0].Plural(), v[1].Name())
+	case TwoPair:
+		return fmt.Sprintf("Two pair, %s and %s, %s kicker", v[0].Plural(), v[1].Plural(), v[2].Name())
+	case ThreeOfAKind:
+		return fmt.Sprintf("Three of a kind, %s, %s kicker", v[0].Plural(), v[1].Name())
+	case Straight:
+		return fmt.Sprintf("Straight, %s high", v[0].Name())
+	case Flush:
+		return fmt.Sprintf("Flush, %s high", v[0].Name())
+	case FullHouse:
+		return fmt.Sprintf("Full house, %s full of %s", v[0].Plural(), v[1].Plural())
+	case FourOfAKind:
+		return fmt.Sprintf("Four of a kind, %s, %s kicker", v[0].Plural(), v[1].Name())
+	case StraightFlush:
+		if v[0] == Ace {
+			return "Royal flush"
+		}
+		return fmt.Sprintf("Straight flush, %s high", v[0].Name())
+	default:
+		return fmt.Sprintf("High card, %s, %s kicker", v[0].Name(), v[1].Name())
+	}
+}
+
+// EvaluateBestHandCards is EvaluateBestHand that also returns the five cards
+// forming the hand, ordered as they are read: the paired ranks first, then the
+// kickers, and straights from their high card.
+func EvaluateBestHandCards(cards []Card) (HandRank, []Card, error) {
+	if len(cards) < 5 {
+		return HandRank{}, nil, errors.New("at least five cards required")
+	}
+	rank, five := bestFive(GameHoldem, nil, cards)
+	return rank, five, nil
+}
+
+// EvaluateHandCards is EvaluateHand that also returns the five cards forming
+// the hand, ordered like EvaluateBestHandCards.
+func EvaluateHandCards(g Game, hole, board []Card) (HandRank, []Card, error) {
+	if _, err := EvaluateHand(g, hole, board); err != nil {
+		return HandRank{}, nil, err
+	}
+	if g.IsOmaha() {
+		rank, five := bestFive(g, hole, board)
+		return rank, five, nil
+	}
+	rank, five := bestFive(g, nil, append(append([]Card(nil), hole...), board...))
+	return rank, five, nil
+}
+
+// bestFive tries every five-card hand: any five of board when hole is empty,
+// otherwise exactly two hole and three board cards as in Omaha.
+func bestFive(g Game, hole, board []Card) (HandRank, []Card) {
+	var best HandRank
+	var bestCards []Card
+	try := func(five []Card) {
+		rank := g.rules().evaluate(NewCardSet(five...))
+		if bestCards == nil || g.Compare(rank, best) > 0 {
+			best, bestCards = rank, append([]Card(nil), five...)
+		}
+	}
+
+	five := make([]Card, 0, 5)
+	var pick func(from []Card, start, need int, done func())
+	pick = func(from []Card, start, need int, done func()) {
+		if need == 0 {
+			done()
+			return
+		}
+		for i := start; i <= len(from)-need; i++ {
+			five = append(five, from[i])
+			pick(from, i+1, need-1, done)
+			five = five[:len(five)-1]
+		}
+	}
+	if len(hole) == 0 {
+		pick(board, 0, 5, func() { try(five) })
+	} else {
+		pick(hole, 0, 2, func() { pick(board, 0, 3, func() { try(five) }) })
+	}
+
+	orderHandCards(best, bestCards)
+	return best, bestCards
+}
+
+// orderHandCards sorts the cards by how many of their rank the hand holds,
+// then by rank, moving the ace behind the five of a wheel.
+func orderHandCards(rank HandRank, cards []Card) {
+	count := make(map[Rank]int)
+	for _, c := range cards {
+		count[c.Rank]++
+	}
+	wheel := (rank.Category == Straight || rank.Category == StraightFlush) && rank.Values[0] != Ace
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := cards[i].Rank, cards[j].Rank
+		if wheel {
+			if a == Ace {
+				return false
+			}
+			if b == Ace {
+				return true
+			}
+		}
+		if count[a] != count[b] {
+			return count[a] > count[b]
+		}
+		return a > b
+	})
+}
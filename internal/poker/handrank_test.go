@@ -0,0 +1,83 @@
+package poker
+
+import "testing"
+
+func TestHandRankString(t *testing.T) {
+	tests := []struct {
+		cards []string
+		want  string
+	}{
+		{[]string{"Ah", "Kd", "9c", "7s", "3h"}, "High card, Ace, King kicker"},
+		{[]string{"Ah", "Ad", "Kc", "Qd", "Jh"}, "Pair of Aces, King kicker"},
+		{[]string{"Ah", "Ad", "7c", "7s", "Qd", "3h", "2s"}, "Two pair, Aces and Sevens, Queen kicker"},
+		{[]string{"6h", "6d", "6s", "Kd", "Qc"}, "Three of a kind, Sixes, King kicker"},
+		{[]string{"Ah", "2d", "3c", "4s", "5d"}, "Straight, Five high"},
+		{[]string{"Kh", "9h", "7h", "5h", "2h"}, "Flush, King high"},
+		{[]string{"Kh", "Kd", "Ks", "7d", "7c"}, "Full house, Kings full of Sevens"},
+		{[]string{"9h", "9d", "9c", "9s", "Ad"}, "Four of a kind, Nines, Ace kicker"},
+		{[]string{"9h", "8h", "7h", "6h", "5h"}, "Straight flush, Nine high"},
+		{[]string{"Ah", "Kh", "Qh", "Jh", "Th"}, "Royal flush"},
+	}
+	for _, tc := range tests {
+		rank, err := EvaluateBestHand(cards(tc.cards...))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := rank.String(); got != tc.want {
+			t.Fatalf("%v: expected %q, got %q", tc.cards, tc.want, got)
+		}
+	}
+}
+
+func TestEvaluateBestHandCards(t *testing.T) {
+	tests := []struct {
+		cards []string
+		want  string
+	}{
+		{[]string{"7c", "Ah", "Qd", "7s", "Ad", "3h", "2s"}, "Ah Ad 7c 7s Qd"},
+		{[]string{"Kh", "2c", "9h", "7h", "5h", "Qd", "2h"}, "Kh 9h 7h 5h 2h"},
+		{[]string{"Ah", "2d", "3c", "4s", "5d", "Kc", "Kd"}, "5d 4s 3c 2d Ah"},
+		{[]string{"Kh", "Kd", "7d", "Ks", "7c", "7h", "2c"}, "Kh Kd Ks 7d 7c"},
+	}
+	for _, tc := range tests {
+		rank, five, err := EvaluateBestHandCards(cards(tc.cards...))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, _ := EvaluateBestHand(cards(tc.cards...)); rank != want {
+			t.Fatalf("%v: expected %+v, got %+v", tc.cards, want, rank)
+		}
+		if got := cardsText(five); got != tc.want {
+			t.Fatalf("%v: expected cards %q, got %q", tc.cards, tc.want, got)
+		}
+	}
+
+	if _, _, err := EvaluateBestHandCards(cards("Ah", "Kh")); err == nil {
+		t.Fatal("expected error for too few cards")
+	}
+}
+
+func TestEvaluateHandCardsOmaha(t *testing.T) {
+	// The board shows four hearts, but Omaha needs two hole cards of the suit.
+	rank, five, err := EvaluateHandCards(GameOmaha4, cards("Ah", "Kd", "Qs", "Qc"), cards("Kh", "9h", "7h", "5h", "Qh"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rank.Category != ThreeOfAKind || rank.Values[0] != Queen {
+		t.Fatalf("expected trip queens, got %v", rank)
+	}
+	if got := cardsText(five); got != "Qs Qc Qh Kh 9h" {
+		t.Fatalf("unexpected cards %q", got)
+	}
+}
+
+func cardsText(cs []Card) string {
+	text := ""
+	for i, c := range cs {
+		if i > 0 {
+			text += " "
+		}
+		text += c.String()
+	}
+	return text
+}
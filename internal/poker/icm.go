@@ -0,0 +1,170 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// MaxICMPlayers bounds the table size of ICM calculations, which grow with
+// the number of player subsets.
+const MaxICMPlayers = 10
+
+// ICM returns each player's share of the prize pool under the Independent
+// Chip Model: a player finishes first with probability proportional to their
+// stack, and the remaining places are decided the same way among the rest.
+// Payouts list the prizes from first place down. Players with an empty stack
+// are already out and split the places below everyone still in.
+func ICM(stacks, payouts []float64) ([]float64, error) {
+	if len(stacks) < 2 || len(stacks) > MaxICMPlayers {
+		return nil, fmt.Errorf("player count must be between 2 and %d", MaxICMPlayers)
+	}
+	if len(payouts) == 0 {
+		return nil, errors.New("at least one payout is required")
+	}
+	var total float64
+	live := 0
+	for _, s := range stacks {
+		if s < 0 {
+			return nil, errors.New("stacks cannot be negative")
+		}
+		if s > 0 {
+			live++
+		}
+		total += s
+	}
+	if live == 0 {
+		return nil, errors.New("at least one stack must hold chips")
+	}
+	for _, p := range payouts {
+		if p < 0 {
+			return nil, errors.New("payouts cannot be negative")
+		}
+	}
+
+	n := len(stacks)
+	prize := func(place int) float64 {
+		if place < len(payouts) {
+			return payouts[place]
+		}
+		return 0
+	}
+
+	// prob[mask] is the probability that exactly the players in mask took the
+	// first bits.OnesCount(mask) places, in any order.
+	prob := make([]float64, 1<<n)
+	chips := make([]float64, 1<<n)
+	prob[0] = 1
+	for mask := 1; mask < len(chips); mask++ {
+		low := mask & -mask
+		chips[mask] = chips[mask&^low] + stacks[bits.TrailingZeros(uint(low))]
+	}
+
+	equity := make([]float64, n)
+	places := min(len(payouts), live)
+	for mask, p := range prob {
+		place := bits.OnesCount(uint(mask))
+		if p == 0 || place >= places {
+			continue
+		}
+		remaining := total - chips[mask]
+		for i, s := range stacks {
+			if mask&(1<<i) != 0 || s == 0 {
+				continue
+			}
+			q := p * s / remaining
+			equity[i] += q * payouts[place]
+			prob[mask|1<<i] += q
+		}
+	}
+
+	if busted := n - live; busted > 0 {
+		var share float64
+		for place := live; place < n; place++ {
+			share += prize(place)
+		}
+		for i, s := range stacks {
+			if s == 0 {
+				equity[i] = share / float64(busted)
+			}
+		}
+	}
+	return equity, nil
+}
+
+// AllInDecision describes an all-in confrontation between two players, such
+// as calling a push.
+type AllInDecision struct {
+	// Stacks are the chips behind each player, excluding Pot.
+	Stacks  []float64
+	Payouts []float64
+	// Hero and Villain index Stacks.
+	Hero, Villain int
+	// Pot holds dead chips already in the middle, such as blinds and antes;
+	// the villain takes them when the hero folds.
+	Pot float64
+	// Equity is the hero's chance to win the all-in, in percent with ties
+	// already split.
+	Equity float64
+}
+
+// AllInAdvice compares folding with getting all-in under ICM.
+type AllInAdvice struct {
+	// FoldEV and CallEV are the hero's prize equity after each action.
+	FoldEV float64
+	CallEV float64
+	// RequiredEquity is the break-even equity of getting all-in, in percent.
+	RequiredEquity float64
+	// ChipRequiredEquity is the break-even equity in chips, for comparison;
+	// the gap to RequiredEquity is the ICM risk premium.
+	ChipRequiredEquity float64
+}
+
+// AdviseAllIn returns the ICM value of folding and of getting all-in. Only
+// the smaller of the two stacks is at risk.
+func AdviseAllIn(d AllInDecision) (AllInAdvice, error) {
+	n := len(d.Stacks)
+	if d.Hero < 0 || d.Hero >= n || d.Villain < 0 || d.Villain >= n || d.Hero == d.Villain {
+		return AllInAdvice{}, errors.New("hero and villain must be two different players")
+	}
+	if d.Equity < 0 || d.Equity > 100 {
+		return AllInAdvice{}, errors.New("equity must be between 0 and 100")
+	}
+	if d.Pot < 0 {
+		return AllInAdvice{}, errors.New("pot cannot be negative")
+	}
+	risk := min(d.Stacks[d.Hero], d.Stacks[d.Villain])
+	if risk <= 0 {
+		return AllInAdvice{}, errors.New("both players must have chips")
+	}
+
+	after := func(heroDelta, villainDelta float64) ([]float64, error) {
+		stacks := append([]float64(nil), d.Stacks...)
+		stacks[d.Hero] += heroDelta
+		stacks[d.Villain] += villainDelta
+		return ICM(stacks, d.Payouts)
+	}
+	fold, err := after(0, d.Pot)
+	if err != nil {
+		return AllInAdvice{}, err
+	}
+	win, err := after(risk+d.Pot, -risk)
+	if err != nil {
+		return AllInAdvice{}, err
+	}
+	lose, err := after(-risk, risk+d.Pot)
+	if err != nil {
+		return AllInAdvice{}, err
+	}
+
+	e := d.Equity / 100
+	a := AllInAdvice{
+		FoldEV:             fold[d.Hero],
+		CallEV:             e*win[d.Hero] + (1-e)*lose[d.Hero],
+		ChipRequiredEquity: risk / (2*risk + d.Pot) * 100,
+	}
+	if spread := win[d.Hero] - lose[d.Hero]; spread > 0 {
+		a.RequiredEquity = (fold[d.Hero] - lose[d.Hero]) / spread * 100
+	}
+	return a, nil
+}
@@ -0,0 +1,131 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestICMHeadsUp(t *testing.T) {
+	equity, err := ICM([]float64{1000, 3000}, []float64{70, 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(equity[0]-40) > 1e-9 || math.Abs(equity[1]-60) > 1e-9 {
+		t.Fatalf("unexpected equity %v", equity)
+	}
+}
+
+func TestICMThreePlayers(t *testing.T) {
+	equity, err := ICM([]float64{5000, 3000, 2000}, []float64{50, 30, 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// First: 0.5; second: 0.3*5/7 + 0.2*5/8; third: the rest.
+	second := 0.3*5/7 + 0.2*5.0/8
+	want := 0.5*50 + second*30 + (1-0.5-second)*20
+	if math.Abs(equity[0]-want) > 1e-9 {
+		t.Fatalf("expected %.4f for the chip leader, got %.4f", want, equity[0])
+	}
+	if sum := equity[0] + equity[1] + equity[2]; math.Abs(sum-100) > 1e-9 {
+		t.Fatalf("expected the prize pool to be shared out, got %.4f", sum)
+	}
+	if !(equity[0] > equity[1] && equity[1] > equity[2]) {
+		t.Fatalf("expected equity to follow the stacks, got %v", equity)
+	}
+	if equity[0]/equity[2] >= 2.5 {
+		t.Fatalf("expected ICM to compress the chip ratio, got %v", equity)
+	}
+}
+
+func TestICMBustedPlayer(t *testing.T) {
+	equity, err := ICM([]float64{1000, 0, 1000}, []float64{50, 30, 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if equity[1] != 20 || math.Abs(equity[0]-40) > 1e-9 || math.Abs(equity[2]-40) > 1e-9 {
+		t.Fatalf("unexpected equity %v", equity)
+	}
+}
+
+func TestICMErrors(t *testing.T) {
+	cases := []struct {
+		stacks, payouts []float64
+	}{
+		{[]float64{1000}, []float64{100}},
+		{make([]float64, MaxICMPlayers+1), []float64{100}},
+		{[]float64{1000, 1000}, nil},
+		{[]float64{1000, -1}, []float64{100}},
+		{[]float64{0, 0}, []float64{100}},
+		{[]float64{1000, 1000}, []float64{100, -10}},
+	}
+	for _, tc := range cases {
+		if _, err := ICM(tc.stacks, tc.payouts); err == nil {
+			t.Fatalf("expected error for stacks %v and payouts %v", tc.stacks, tc.payouts)
+		}
+	}
+}
+
+func TestAdviseAllInWinnerTakesAll(t *testing.T) {
+	advice, err := AdviseAllIn(AllInDecision{
+		Stacks:  []float64{1000, 1000},
+		Payouts: []float64{100},
+		Hero:    0,
+		Villain: 1,
+		Equity:  55,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(advice.RequiredEquity-50) > 1e-9 || advice.ChipRequiredEquity != 50 {
+		t.Fatalf("expected ICM to match chips when the winner takes all, got %+v", advice)
+	}
+	if advice.FoldEV != 50 || math.Abs(advice.CallEV-55) > 1e-9 {
+		t.Fatalf("unexpected advice %+v", advice)
+	}
+}
+
+func TestAdviseAllInBubble(t *testing.T) {
+	// Two places paid equally: busting on the bubble costs far more than
+	// doubling up gains.
+	d := AllInDecision{
+		Stacks:  []float64{1000, 1000, 1000},
+		Payouts: []float64{50, 50},
+		Hero:    0,
+		Villain: 2,
+		Pot:     100,
+		Equity:  60,
+	}
+	advice, err := AdviseAllIn(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.RequiredEquity <= advice.ChipRequiredEquity || advice.RequiredEquity <= d.Equity {
+		t.Fatalf("expected a bubble risk premium, got %+v", advice)
+	}
+	if advice.CallEV >= advice.FoldEV {
+		t.Fatalf("expected folding to be better, got %+v", advice)
+	}
+
+	d.Equity = advice.RequiredEquity
+	if breakEven, _ := AdviseAllIn(d); math.Abs(breakEven.CallEV-breakEven.FoldEV) > 1e-9 {
+		t.Fatalf("expected calling to break even at the required equity, got %+v", breakEven)
+	}
+}
+
+func TestAdviseAllInErrors(t *testing.T) {
+	base := AllInDecision{Stacks: []float64{1000, 500}, Payouts: []float64{100}, Villain: 1, Equity: 50}
+	for _, mutate := range []func(*AllInDecision){
+		func(d *AllInDecision) { d.Villain = 0 },
+		func(d *AllInDecision) { d.Villain = 2 },
+		func(d *AllInDecision) { d.Equity = 101 },
+		func(d *AllInDecision) { d.Pot = -1 },
+		func(d *AllInDecision) { d.Stacks = []float64{1000, 0} },
+	} {
+		d := base
+		d.Stacks = append([]float64(nil), base.Stacks...)
+		mutate(&d)
+		if _, err := AdviseAllIn(d); err == nil {
+			t.Fatalf("expected error for %+v", d)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OutKind classifies a card that may come on the next street.
+type OutKind int
+
+const (
+	// OutNeutral leaves the hero's hand as it was or only improves the board.
+	OutNeutral OutKind = iota
+	// OutLive improves the hero to the best hand against the opponent model.
+	OutLive
+	// OutDead improves the hero's hand but the opponent model still holds a
+	// better one more often than not.
+	OutDead
+)
+
+// OutsConfig describes an outs calculation on the flop or the turn.
+type OutsConfig struct {
+	Game  Game
+	Hero  []Card
+	Board []Card
+	// Villain constrains the opponent's holding; an empty range means any two
+	// cards.
+	Villain Range
+}
+
+// OutCard is one possible next card with the hand it gives the hero.
+type OutCard struct {
+	Card Card
+	Kind OutKind
+	Hand HandRank
+	// Ahead is the share of opponent holdings, in percent, the hero beats
+	// after this card, ties counted as half.
+	Ahead float64
+}
+
+// OutsResult lists every unseen card and the chance of hitting an out.
+type OutsResult struct {
+	// Cards holds every card not in the hero's hand or on the board, in deck
+	// order.
+	Cards []OutCard
+	Live  int
+	Dead  int
+	// NextCard is the percentage of hitting a live out on the next card,
+	// ByRiver the percentage of hitting at least one by the river.
+	NextCard float64
+	ByRiver  float64
+}
+
+// CalculateOuts classifies every possible next card against the opponent
+// model. A card is an out when it lifts the hero's hand category above what
+// the board alone shows; the out is live when the hero then beats at least
+// half of the opponent's holdings.
+func CalculateOuts(cfg OutsConfig) (OutsResult, error) {
+	if cfg.Game.IsOmaha() {
+		return OutsResult{}, errors.New("outs are not supported in Omaha")
+	}
+	if len(cfg.Hero) != cfg.Game.HoleCards() {
+		return OutsResult{}, fmt.Errorf("hero must have exactly %d hole cards", cfg.Game.HoleCards())
+	}
+	if len(cfg.Board) != 3 && len(cfg.Board) != 4 {
+		return OutsResult{}, errors.New("outs need a flop or a turn board")
+	}
+	known := append(append([]Card(nil), cfg.Hero...), cfg.Board...)
+	for _, c := range known {
+		if !cfg.Game.HasCard(c) {
+			return OutsResult{}, fmt.Errorf("card %s is not in the %s deck", c, cfg.Game)
+		}
+	}
+	hero, board := NewCardSet(cfg.Hero...), NewCardSet(cfg.Board...)
+	if (hero | board).Len() != len(known) {
+		return OutsResult{}, errors.New("duplicate cards provided")
+	}
+
+	villains := cfg.Villain.compatible(hero | board | cfg.Game.excluded())
+	if cfg.Villain.IsEmpty() {
+		villains = anyTwo(cfg.Game.BuildDeck(known))
+	}
+	if len(villains) == 0 {
+		return OutsResult{}, errors.New("opponent range conflicts with the known cards")
+	}
+
+	r := cfg.Game.rules()
+	current := r.strength(cfg.Game.evaluate(hero, board).Category)
+
+	var res OutsResult
+	for _, c := range cfg.Game.BuildDeck(known) {
+		next := board.Add(c)
+		out := OutCard{Card: c, Hand: cfg.Game.evaluate(hero, next)}
+		strength := r.strength(out.Hand.Category)
+		if strength > current && strength > r.strength(r.evaluate(next).Category) {
+			out.Ahead = aheadShare(cfg.Game, out.Hand, next, villains)
+			out.Kind = OutDead
+			if out.Ahead >= 50 {
+				out.Kind = OutLive
+			}
+		}
+		switch out.Kind {
+		case OutLive:
+			res.Live++
+		case OutDead:
+			res.Dead++
+		}
+		res.Cards = append(res.Cards, out)
+	}
+
+	unseen := len(res.Cards)
+	res.NextCard = percentage(res.Live, unseen)
+	res.ByRiver = res.NextCard
+	if len(cfg.Board) == 3 {
+		miss := binomial(unseen-res.Live, 2)
+		res.ByRiver = 100 - percentage(miss, binomial(unseen, 2))
+	}
+	return res, nil
+}
+
+// aheadShare returns the percentage of opponent combos the hero's hand beats
+// on the board, ignoring combos that use a board card.
+func aheadShare(g Game, heroRank HandRank, board CardSet, villains []Combo) float64 {
+	var score, total int
+	for _, combo := range villains {
+		hand := combo.set()
+		if hand&board != 0 {
+			continue
+		}
+		total += 2
+		switch g.Compare(heroRank, g.evaluate(hand, board)) {
+		case 1:
+			score += 2
+		case 0:
+			score++
+		}
+	}
+	return percentage(score, total)
+}
+
+// anyTwo lists every two-card combo from the deck.
+func anyTwo(deck []Card) []Combo {
+	combos := make([]Combo, 0, len(deck)*(len(deck)-1)/2)
+	for i := 0; i < len(deck)-1; i++ {
+		for j := i + 1; j < len(deck); j++ {
+			combos = append(combos, Combo{deck[i], deck[j]})
+		}
+	}
+	return combos
+}
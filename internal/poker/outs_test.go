@@ -0,0 +1,73 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateOutsFlushDraw(t *testing.T) {
+	res, err := CalculateOuts(OutsConfig{
+		Hero:  cards("Ah", "Kh"),
+		Board: cards("9h", "5h", "2c"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Cards) != 47 {
+		t.Fatalf("expected 47 unseen cards, got %d", len(res.Cards))
+	}
+	// Nine hearts and six overcards against any two cards.
+	if res.Live != 15 || res.Dead != 0 {
+		t.Fatalf("expected 15 live outs, got %d live and %d dead", res.Live, res.Dead)
+	}
+	if math.Abs(res.NextCard-100*15.0/47) > 1e-9 {
+		t.Fatalf("unexpected next card odds %.4f", res.NextCard)
+	}
+	if want := 100 - 100*float64(32*31)/float64(47*46); math.Abs(res.ByRiver-want) > 1e-9 {
+		t.Fatalf("expected %.4f by the river, got %.4f", want, res.ByRiver)
+	}
+	for _, out := range res.Cards {
+		if out.Card.Rank == Nine && out.Kind != OutNeutral {
+			t.Fatalf("pairing the board is not an out, got %+v", out)
+		}
+	}
+}
+
+func TestCalculateOutsAgainstRange(t *testing.T) {
+	res, err := CalculateOuts(OutsConfig{
+		Hero:    cards("Ah", "Kh"),
+		Board:   cards("9h", "5h", "2c", "Td"),
+		Villain: MustParseRange("99"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Overcards never beat a set, while 2h and Th fill the set up.
+	if res.Live != 7 || res.Dead != 8 {
+		t.Fatalf("expected 7 live and 8 dead outs, got %d and %d", res.Live, res.Dead)
+	}
+	if res.ByRiver != res.NextCard {
+		t.Fatal("expected one card to come on the turn")
+	}
+	for _, out := range res.Cards {
+		if out.Card == MustParseCard("2h") && out.Kind != OutDead {
+			t.Fatalf("expected 2h to be a dead out, got %+v", out)
+		}
+	}
+}
+
+func TestCalculateOutsValidation(t *testing.T) {
+	cases := []OutsConfig{
+		{Hero: cards("Ah", "Kh"), Board: cards("9h", "5h")},
+		{Hero: cards("Ah", "Kh"), Board: cards("9h", "5h", "2c", "3c", "4c")},
+		{Hero: cards("Ah", "Kh"), Board: cards("Ah", "5h", "2c")},
+		{Game: GameOmaha4, Hero: cards("Ah", "Kh", "Qh", "Jh"), Board: cards("9h", "5h", "2c")},
+		{Game: GameShortDeck, Hero: cards("Ah", "Kh"), Board: cards("9h", "5h", "7c")},
+		{Hero: cards("Ah", "Kh"), Board: cards("9h", "5h", "2c"), Villain: MustParseRange("AhKh")},
+	}
+	for i, cfg := range cases {
+		if _, err := CalculateOuts(cfg); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package poker
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ../../cmd/preflopgen -out preflop.txt
+
+// MaxPreflopOpponents is the largest table size covered by the preflop table.
+const MaxPreflopOpponents = 8
+
+//go:embed preflop.txt
+var preflopData string
+
+var preflopStyles = []PlayerStyle{StyleAny, StyleBalanced, StyleTight, StyleLoose}
+
+// preflopKey identifies a table entry. Opponents are keyed by the percentage
+// of hands they play rather than by style, so entries stay valid only for
+// styles that still map to the same percentage.
+type preflopKey struct {
+	hand      string
+	opponents int
+	percent   float64
+}
+
+type preflopEntry struct {
+	win, tie, lose float64
+	// equity and equityMargin are stored since pot shares of multiway ties
+	// cannot be recovered from the tie rate.
+	equity, equityMargin float64
+}
+
+var (
+	preflopOnce   sync.Once
+	preflopTable  map[preflopKey]preflopEntry
+	preflopTrials int
+	preflopErr    error
+)
+
+// StartingHands lists the 169 canonical Hold'em starting hands: pairs from AA
+// down, then for each high card the suited and offsuit hands by kicker.
+func StartingHands() []string {
+	hands := make([]string, 0, 169)
+	for r := Ace; r >= Two; r-- {
+		hands = append(hands, handClass{high: r, low: r}.String())
+	}
+	for high := Ace; high > Two; high-- {
+		for low := high - 1; low >= Two; low-- {
+			hands = append(hands, handClass{high: high, low: low, suited: 's'}.String())
+			hands = append(hands, handClass{high: high, low: low, suited: 'o'}.String())
+		}
+	}
+	return hands
+}
+
+// CanonicalCombo returns the representative combo of a starting hand such as
+// "AKs" or "T9o". Hands that differ only by a permutation of suits have the
+// same equity preflop, so one combo stands for all of them.
+func CanonicalCombo(hand string) (Combo, error) {
+	class, err := parseHandClass(hand)
+	if err != nil {
+		return Combo{}, err
+	}
+	if class.high != class.low && class.suited == 0 {
+		return Combo{}, fmt.Errorf("starting hand %s must be suited or offsuit", hand)
+	}
+	return class.combos()[0], nil
+}
+
+// LookupPreflop returns the precomputed Hold'em equity of two hole cards
+// against opponents of one style on an empty board. It reports false when the
+// spot is not covered by the table, including custom percentages the table
+// was not built for.
+func LookupPreflop(hero []Card, opponents int, style PlayerStyle) (SimulationResult, bool) {
+	if len(hero) != 2 || hero[0] == hero[1] || opponents < 1 || opponents > MaxPreflopOpponents {
+		return SimulationResult{}, false
+	}
+	preflopOnce.Do(func() {
+		preflopTable, preflopTrials, preflopErr = parsePreflopTable(strings.NewReader(preflopData))
+	})
+	if preflopErr != nil {
+		return SimulationResult{}, false
+	}
+
+	entry, ok := preflopTable[preflopKey{hand: Combo{hero[0], hero[1]}.Class(), opponents: opponents, percent: style.Percent()}]
+	if !ok {
+		return SimulationResult{}, false
+	}
+	res := SimulationResult{
+		Win:          entry.win,
+		Tie:          entry.tie,
+		Lose:         entry.lose,
+		Equity:       entry.equity,
+		EquityMargin: entry.equityMargin,
+		Method:       MethodPrecomputed,
+		Samples:      preflopTrials,
+	}
+	res.WinMargin = marginOfError(int(entry.win*float64(preflopTrials)/100), preflopTrials)
+	res.TieMargin = marginOfError(int(entry.tie*float64(preflopTrials)/100), preflopTrials)
+	res.LoseMargin = marginOfError(int(entry.lose*float64(preflopTrials)/100), preflopTrials)
+	return res, true
+}
+
+// GeneratePreflopTable simulates every starting hand against 1 to
+// MaxPreflopOpponents opponents of every style and writes the table embedded
+// by LookupPreflop. Progress, when set, is called after every entry.
+func GeneratePreflopTable(w io.Writer, trials int, seed int64, progress func(done, total int)) error {
+	hands := StartingHands()
+	total := len(hands) * MaxPreflopOpponents * len(preflopStyles)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Preflop equity of the 169 Hold'em starting hands. Generated by cmd/preflopgen; do not edit.")
+	fmt.Fprintf(bw, "# trials=%d seed=%d\n", trials, seed)
+	fmt.Fprintln(bw, "# hand opponents percent win tie lose equity equity_margin")
+
+	done := 0
+	for _, hand := range hands {
+		combo, err := CanonicalCombo(hand)
+		if err != nil {
+			return err
+		}
+		for opponents := 1; opponents <= MaxPreflopOpponents; opponents++ {
+			for _, style := range preflopStyles {
+				res, err := SimulateWinProbability(SimulationConfig{
+					Hero:       combo[:],
+					Opponents:  opponents,
+					Style:      style,
+					Trials:     trials,
+					Seed:       seed + int64(done),
+					ExactLimit: -1,
+				})
+				if err != nil {
+					return fmt.Errorf("%s against %d: %w", hand, opponents, err)
+				}
+				fmt.Fprintf(bw, "%s %d %g %.3f %.3f %.3f %.3f %.3f\n", hand, opponents, style.Percent(),
+					res.Win, res.Tie, res.Lose, res.Equity, res.EquityMargin)
+				done++
+				if progress != nil {
+					progress(done, total)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func parsePreflopTable(r io.Reader) (map[preflopKey]preflopEntry, int, error) {
+	table := make(map[preflopKey]preflopEntry)
+	trials := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			for _, field := range strings.Fields(line[1:]) {
+				if value, ok := strings.CutPrefix(field, "trials="); ok {
+					trials, _ = strconv.Atoi(value)
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 8 {
+			return nil, 0, fmt.Errorf("malformed preflop line: %s", line)
+		}
+		opponents, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, 0, fmt.Errorf("malformed preflop line: %s", line)
+		}
+		percent, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, 0, fmt.Errorf("malformed preflop line: %s", line)
+		}
+		var values [5]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(fields[3+i], 64); err != nil {
+				return nil, 0, fmt.Errorf("malformed preflop line: %s", line)
+			}
+		}
+		table[preflopKey{hand: fields[0], opponents: opponents, percent: percent}] = preflopEntry{
+			win: values[0], tie: values[1], lose: values[2], equity: values[3], equityMargin: values[4],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	return table, trials, nil
+}
@@ -0,0 +1,115 @@
+package poker
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStartingHands(t *testing.T) {
+	hands := StartingHands()
+	if len(hands) != 169 || hands[0] != "AA" || hands[len(hands)-1] != "32o" {
+		t.Fatalf("unexpected starting hands: %d, %s ... %s", len(hands), hands[0], hands[len(hands)-1])
+	}
+
+	combos := 0
+	seen := make(map[string]bool)
+	for _, hand := range hands {
+		if seen[hand] {
+			t.Fatalf("duplicate hand %s", hand)
+		}
+		seen[hand] = true
+
+		combo, err := CanonicalCombo(hand)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", hand, err)
+		}
+		if combo.Class() != hand {
+			t.Fatalf("expected %s to stand for %s", combo, hand)
+		}
+		combos += MustParseRange(hand).Len()
+	}
+	if combos != 1326 {
+		t.Fatalf("expected the starting hands to cover 1326 combos, got %d", combos)
+	}
+
+	for _, hand := range []string{"AK", "ZZ", "AKx"} {
+		if _, err := CanonicalCombo(hand); err == nil {
+			t.Fatalf("expected error for %s", hand)
+		}
+	}
+}
+
+func TestLookupPreflop(t *testing.T) {
+	res, ok := LookupPreflop(cards("Ah", "Kh"), 1, StyleBalanced)
+	if !ok {
+		t.Fatal("expected AKs heads-up to be in the table")
+	}
+	if res.Method != MethodPrecomputed || res.Samples == 0 || res.WinMargin == 0 {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if sum := res.Win + res.Tie + res.Lose; math.Abs(sum-100) > 0.01 {
+		t.Fatalf("probabilities must sum to 100, got %.3f", sum)
+	}
+	if math.Abs(res.Equity-(res.Win+res.Tie/2)) > 0.01 || res.EquityMargin == 0 {
+		t.Fatalf("expected heads-up equity to split ties in half, got %+v", res)
+	}
+
+	// Suit permutations and card order map to the same entry.
+	for _, hero := range [][]Card{cards("Kc", "Ac"), cards("As", "Ks")} {
+		if other, _ := LookupPreflop(hero, 1, StyleBalanced); other.Win != res.Win || other.Tie != res.Tie {
+			t.Fatalf("expected %v to match AhKh", hero)
+		}
+	}
+	if offsuit, _ := LookupPreflop(cards("Ah", "Kd"), 1, StyleBalanced); offsuit.Win >= res.Win {
+		t.Fatalf("expected AKo to win less often than AKs, got %.2f vs %.2f", offsuit.Win, res.Win)
+	}
+
+	for _, opponents := range []int{0, MaxPreflopOpponents + 1} {
+		if _, ok := LookupPreflop(cards("Ah", "Kh"), opponents, StyleBalanced); ok {
+			t.Fatalf("expected no entry for %d opponents", opponents)
+		}
+	}
+	if _, ok := LookupPreflop(cards("Ah", "Ah"), 1, StyleBalanced); ok {
+		t.Fatal("expected no entry for a duplicated card")
+	}
+}
+
+func TestLookupPreflopMatchesSimulation(t *testing.T) {
+	table, _, err := parsePreflopTable(strings.NewReader(preflopData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 169 * MaxPreflopOpponents * len(preflopStyles); len(table) != want {
+		t.Fatalf("expected %d entries, got %d", want, len(table))
+	}
+
+	cfg := SimulationConfig{Hero: cards("7c", "7d"), Opponents: 3, Style: StyleTight, Trials: 20000, Seed: 10}
+	sim, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, ok := LookupPreflop(cfg.Hero, cfg.Opponents, cfg.Style)
+	if !ok {
+		t.Fatal("expected 77 to be in the table")
+	}
+	if math.Abs(res.Win-sim.Win) > 4*sim.WinMargin {
+		t.Fatalf("table and simulation disagree: %.2f vs %.2f", res.Win, sim.Win)
+	}
+}
+
+func TestParsePreflopTable(t *testing.T) {
+	table, trials, err := parsePreflopTable(strings.NewReader("# trials=500 seed=1\nAA 1 15 80.1 1.9 18.0 81.05 3.4\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trials != 500 || table[preflopKey{hand: "AA", opponents: 1, percent: 15}] != (preflopEntry{80.1, 1.9, 18.0, 81.05, 3.4}) {
+		t.Fatalf("unexpected table %v (%d trials)", table, trials)
+	}
+
+	for _, data := range []string{"AA 1 15 80.1 1.9 18.0", "AA one 15 80 1 19 80.5 3", "AA 1 tight 80 1 19 80.5 3", "AA 1 0 80 1 19 80.5 3", "AA 1 15 80 1 19 x 3"} {
+		if _, _, err := parsePreflopTable(strings.NewReader(data)); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
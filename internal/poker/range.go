@@ -0,0 +1,257 @@
+package poker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Combo is a concrete pair of hole cards.
+type Combo [2]Card
+
+func (c Combo) String() string {
+	return c[0].String() + c[1].String()
+}
+
+// Class returns the suit-agnostic starting hand of the combo, e.g. "AKs",
+// "AKo" or "77".
+func (c Combo) Class() string {
+	high, low := c[0], c[1]
+	if low.Rank > high.Rank {
+		high, low = low, high
+	}
+	class := handClass{high: high.Rank, low: low.Rank}
+	if high.Rank != low.Rank {
+		class.suited = 'o'
+		if high.Suit == low.Suit {
+			class.suited = 's'
+		}
+	}
+	return class.String()
+}
+
+func (c Combo) set() CardSet {
+	return NewCardSet(c[0], c[1])
+}
+
+// Range is a set of hole-card combos an opponent may hold, built from
+// standard notation such as "QQ+, AKs, ATo+, 76s-54s, KhQh, 22-88".
+type Range struct {
+	combos []Combo
+	tokens []string
+}
+
+// ParseRange parses comma-separated range notation.
+func ParseRange(text string) (Range, error) {
+	var r Range
+	seen := make(map[CardSet]struct{})
+
+	for _, raw := range strings.Split(text, ",") {
+		token := strings.TrimSpace(raw)
+		if token == "" {
+			continue
+		}
+
+		combos, err := parseRangeToken(token)
+		if err != nil {
+			return Range{}, err
+		}
+		for _, c := range combos {
+			key := c.set()
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			r.combos = append(r.combos, c)
+		}
+		r.tokens = append(r.tokens, token)
+	}
+
+	if len(r.combos) == 0 {
+		return Range{}, fmt.Errorf("range is empty: %q", text)
+	}
+	return r, nil
+}
+
+// MustParseRange is a helper that panics on invalid input; intended for tests.
+func MustParseRange(text string) Range {
+	r, err := ParseRange(text)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Combos returns the distinct combos of the range.
+func (r Range) Combos() []Combo {
+	return append([]Combo(nil), r.combos...)
+}
+
+// Len returns the number of distinct combos in the range.
+func (r Range) Len() int {
+	return len(r.combos)
+}
+
+// IsEmpty reports whether the range holds no combos at all.
+func (r Range) IsEmpty() bool {
+	return len(r.combos) == 0
+}
+
+// String renders the range in the notation it was parsed from.
+func (r Range) String() string {
+	return strings.Join(r.tokens, ", ")
+}
+
+// compatible returns the combos that do not use any of the dead cards.
+func (r Range) compatible(dead CardSet) []Combo {
+	combos := make([]Combo, 0, len(r.combos))
+	for _, c := range r.combos {
+		if c.set()&dead == 0 {
+			combos = append(combos, c)
+		}
+	}
+	return combos
+}
+
+// handClass is a suit-agnostic starting hand such as "AKs" or "77".
+type handClass struct {
+	high, low Rank
+	suited    byte // 's', 'o' or 0 for both
+}
+
+func (h handClass) String() string {
+	s := rankToString[h.high] + rankToString[h.low]
+	if h.suited != 0 {
+		s += string(h.suited)
+	}
+	return s
+}
+
+func (h handClass) combos() []Combo {
+	var combos []Combo
+	for s1 := Clubs; s1 <= Spades; s1++ {
+		for s2 := Clubs; s2 <= Spades; s2++ {
+			if h.high == h.low && s2 <= s1 {
+				continue
+			}
+			if h.suited == 's' && s1 != s2 || h.suited == 'o' && s1 == s2 {
+				continue
+			}
+			combos = append(combos, Combo{{Rank: h.high, Suit: s1}, {Rank: h.low, Suit: s2}})
+		}
+	}
+	return combos
+}
+
+func parseRangeToken(token string) ([]Combo, error) {
+	switch {
+	case strings.Contains(token, "-"):
+		parts := strings.SplitN(token, "-", 2)
+		from, err := parseHandClass(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseHandClass(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		return expandSpan(token, from, to)
+	case strings.HasSuffix(token, "+"):
+		class, err := parseHandClass(strings.TrimSuffix(token, "+"))
+		if err != nil {
+			return nil, err
+		}
+		top := handClass{high: Ace, low: Ace, suited: class.suited}
+		if class.high != class.low {
+			top = handClass{high: class.high, low: class.high - 1, suited: class.suited}
+		}
+		return expandSpan(token, top, class)
+	case len(token) == 4:
+		first, err := ParseCard(token[:2])
+		if err != nil {
+			return nil, fmt.Errorf("range %q: %w", token, err)
+		}
+		second, err := ParseCard(token[2:])
+		if err != nil {
+			return nil, fmt.Errorf("range %q: %w", token, err)
+		}
+		if first == second {
+			return nil, fmt.Errorf("range %q: duplicate card", token)
+		}
+		return []Combo{{first, second}}, nil
+	default:
+		class, err := parseHandClass(token)
+		if err != nil {
+			return nil, err
+		}
+		return class.combos(), nil
+	}
+}
+
+func parseHandClass(token string) (handClass, error) {
+	if len(token) < 2 || len(token) > 3 {
+		return handClass{}, fmt.Errorf("invalid range token: %s", token)
+	}
+
+	first, ok := stringToRank[strings.ToUpper(token[:1])]
+	if !ok {
+		return handClass{}, fmt.Errorf("invalid range rank in %s", token)
+	}
+	second, ok := stringToRank[strings.ToUpper(token[1:2])]
+	if !ok {
+		return handClass{}, fmt.Errorf("invalid range rank in %s", token)
+	}
+	if second > first {
+		first, second = second, first
+	}
+
+	class := handClass{high: first, low: second}
+	if len(token) == 3 {
+		switch strings.ToLower(token[2:]) {
+		case "s":
+			class.suited = 's'
+		case "o":
+			class.suited = 'o'
+		default:
+			return handClass{}, fmt.Errorf("invalid range suffix in %s", token)
+		}
+		if first == second {
+			return handClass{}, fmt.Errorf("pairs cannot be suited or offsuit: %s", token)
+		}
+	}
+	return class, nil
+}
+
+// expandSpan enumerates the classes between from and to inclusive. Spans either
+// move a pair, keep the top card and move the kicker (A5s-A2s), or slide both
+// cards with a fixed gap (76s-54s).
+func expandSpan(token string, from, to handClass) ([]Combo, error) {
+	if from.suited != to.suited || (from.high == from.low) != (to.high == to.low) {
+		return nil, fmt.Errorf("range %q: span ends must be of the same kind", token)
+	}
+	if from.high < to.high || (from.high == to.high && from.low < to.low) {
+		from, to = to, from
+	}
+
+	var highStep Rank
+	switch {
+	case from.high == from.low:
+		highStep = 1
+	case from.high == to.high:
+		highStep = 0
+	case from.high-from.low == to.high-to.low:
+		highStep = 1
+	default:
+		return nil, fmt.Errorf("range %q: unsupported span", token)
+	}
+
+	var combos []Combo
+	for class := from; ; {
+		combos = append(combos, class.combos()...)
+		if class == to {
+			break
+		}
+		class.high -= highStep
+		class.low--
+	}
+	return combos, nil
+}
@@ -0,0 +1,72 @@
+package poker
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input  string
+		combos int
+	}{
+		{"QQ+", 18},
+		{"AKs", 4},
+		{"AKo", 12},
+		{"AK", 16},
+		{"ATo+", 48},
+		{"A2s+", 48},
+		{"76s-54s", 12},
+		{"A5s-A2s", 16},
+		{"KhQh", 1},
+		{"22-88", 42},
+		{"QQ+, AKs", 22},
+		{"QQ+, KK, AhKh, AKs", 22},
+	}
+
+	for _, tc := range tests {
+		r, err := ParseRange(tc.input)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tc.input, err)
+		}
+		if r.Len() != tc.combos {
+			t.Fatalf("%s: expected %d combos, got %d", tc.input, tc.combos, r.Len())
+		}
+	}
+}
+
+func TestParseRangeCombos(t *testing.T) {
+	r := MustParseRange("76s-65s")
+	for _, c := range r.Combos() {
+		if c[0].Suit != c[1].Suit {
+			t.Fatalf("expected suited combo, got %s", c)
+		}
+		if !(c[0].Rank == Seven && c[1].Rank == Six) && !(c[0].Rank == Six && c[1].Rank == Five) {
+			t.Fatalf("unexpected combo %s", c)
+		}
+	}
+
+	if got := MustParseRange("QQ+,  ajs").String(); got != "QQ+, ajs" {
+		t.Fatalf("unexpected string form %q", got)
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, input := range []string{"", "XYs", "AAs", "AKx", "A5s-K2s", "76s-54o", "AhAh", "QQ-AKs"} {
+		if _, err := ParseRange(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestComboClass(t *testing.T) {
+	tests := map[string]string{
+		"AhKh": "AKs",
+		"7d7c": "77",
+		"2cAd": "A2o",
+		"TsJs": "JTs",
+	}
+	for input, want := range tests {
+		combos := MustParseRange(input).Combos()
+		if got := combos[0].Class(); got != want {
+			t.Fatalf("%s: expected %s, got %s", input, want, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ShowdownPlayer is one player's part in a finished hand.
+type ShowdownPlayer struct {
+	Hole []Card
+	// Contributed is every chip the player put into the pot during the hand.
+	Contributed int
+	// Folded players leave their chips in the pot but cannot win it; their
+	// hole cards may be empty.
+	Folded bool
+}
+
+// ShowdownConfig describes a hand that reached the river. Players are listed
+// in seat order starting with the first seat left of the button, which is
+// the order odd chips are handed out in.
+type ShowdownConfig struct {
+	Game    Game
+	Board   []Card
+	Players []ShowdownPlayer
+}
+
+// Pot is the main pot or one of the side pots.
+type Pot struct {
+	Amount int
+	// Eligible lists the players who can win the pot and Winners those who
+	// do, as indexes into the config's players.
+	Eligible []int
+	Winners  []int
+}
+
+// ShowdownResult splits the pot among the players.
+type ShowdownResult struct {
+	// Hands holds each player's best hand; folded players keep the zero value.
+	Hands []HandRank
+	// Pots lists the main pot first, then the side pots in the order they
+	// were created.
+	Pots []Pot
+	// Winnings holds the chips each player collects, including chips nobody
+	// called, which return to the player who put them in.
+	Winnings []int
+}
+
+// ResolveShowdown ranks the players' hands on the board and pays out the main
+// and side pots. A pot is shared by everyone who contributed up to its level,
+// and only players who did not fold and matched that level can win it. Split
+// pots go to the winners in equal parts; the chips left over go one at a
+// time to the winners in seat order.
+func ResolveShowdown(cfg ShowdownConfig) (ShowdownResult, error) {
+	n := len(cfg.Players)
+	if n < 2 {
+		return ShowdownResult{}, errors.New("a showdown needs at least two players")
+	}
+	if len(cfg.Board) != 5 {
+		return ShowdownResult{}, fmt.Errorf("board must have 5 cards, got %d", len(cfg.Board))
+	}
+
+	res := ShowdownResult{Hands: make([]HandRank, n), Winnings: make([]int, n)}
+	seen := NewCardSet(cfg.Board...)
+	known := len(cfg.Board)
+	live := 0
+	for i, p := range cfg.Players {
+		if p.Contributed < 0 {
+			return ShowdownResult{}, fmt.Errorf("player %d: contribution cannot be negative", i+1)
+		}
+		seen |= NewCardSet(p.Hole...)
+		known += len(p.Hole)
+		if p.Folded {
+			continue
+		}
+		hand, err := EvaluateHand(cfg.Game, p.Hole, cfg.Board)
+		if err != nil {
+			return ShowdownResult{}, fmt.Errorf("player %d: %w", i+1, err)
+		}
+		res.Hands[i] = hand
+		live++
+	}
+	if seen.Len() != known {
+		return ShowdownResult{}, errors.New("duplicate cards provided")
+	}
+	if live == 0 {
+		return ShowdownResult{}, errors.New("at least one player must reach the showdown")
+	}
+
+	res.Pots = buildPots(cfg.Players)
+	for i := range res.Pots {
+		pot := &res.Pots[i]
+		if len(pot.Eligible) == 0 {
+			return ShowdownResult{}, errors.New("chips were put in only by players who folded")
+		}
+		pot.Winners = bestPlayers(cfg.Game, res.Hands, pot.Eligible)
+		share := pot.Amount / len(pot.Winners)
+		for _, w := range pot.Winners {
+			res.Winnings[w] += share
+		}
+		for _, w := range pot.Winners[:pot.Amount%len(pot.Winners)] {
+			res.Winnings[w]++
+		}
+	}
+	return res, nil
+}
+
+// buildPots slices the contributions at every level a player stopped at.
+// Chips above the level of every live player, put in by players who later
+// folded, join the pot below them.
+func buildPots(players []ShowdownPlayer) []Pot {
+	var levels []int
+	for _, p := range players {
+		if p.Contributed > 0 {
+			levels = append(levels, p.Contributed)
+		}
+	}
+	sort.Ints(levels)
+
+	var pots []Pot
+	prev := 0
+	for _, level := range levels {
+		if level == prev {
+			continue
+		}
+		pot := Pot{}
+		for i, p := range players {
+			pot.Amount += min(p.Contributed, level) - min(p.Contributed, prev)
+			if !p.Folded && p.Contributed >= level {
+				pot.Eligible = append(pot.Eligible, i)
+			}
+		}
+		prev = level
+
+		if len(pots) > 0 && (len(pot.Eligible) == 0 || slices.Equal(pots[len(pots)-1].Eligible, pot.Eligible)) {
+			pots[len(pots)-1].Amount += pot.Amount
+			continue
+		}
+		pots = append(pots, pot)
+	}
+	return pots
+}
+
+// bestPlayers returns the eligible players holding the best hand, in seat
+// order.
+func bestPlayers(g Game, hands []HandRank, eligible []int) []int {
+	winners := []int{eligible[0]}
+	for _, i := range eligible[1:] {
+		switch g.Compare(hands[i], hands[winners[0]]) {
+		case 1:
+			winners = []int{i}
+		case 0:
+			winners = append(winners, i)
+		}
+	}
+	return winners
+}
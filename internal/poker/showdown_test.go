@@ -0,0 +1,116 @@
+package poker
+
+import "testing"
+
+func TestResolveShowdownSidePots(t *testing.T) {
+	// The short stack has the best hand, the middle stack the second best.
+	res, err := ResolveShowdown(ShowdownConfig{
+		Board: cards("2c", "7d", "9h", "Ts", "3s"),
+		Players: []ShowdownPlayer{
+			{Hole: cards("Ah", "Ad"), Contributed: 100},
+			{Hole: cards("Kh", "Kd"), Contributed: 250},
+			{Hole: cards("Qh", "Qd"), Contributed: 400},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Pots) != 3 {
+		t.Fatalf("expected a main pot, a side pot and uncalled chips, got %+v", res.Pots)
+	}
+	want := []struct {
+		amount   int
+		eligible int
+		winner   int
+	}{{300, 3, 0}, {300, 2, 1}, {150, 1, 2}}
+	for i, w := range want {
+		pot := res.Pots[i]
+		if pot.Amount != w.amount || len(pot.Eligible) != w.eligible || len(pot.Winners) != 1 || pot.Winners[0] != w.winner {
+			t.Fatalf("pot %d: unexpected %+v", i, pot)
+		}
+	}
+	if res.Winnings[0] != 300 || res.Winnings[1] != 300 || res.Winnings[2] != 150 {
+		t.Fatalf("unexpected winnings %v", res.Winnings)
+	}
+	if res.Hands[0].Category != OnePair || res.Hands[0].Values[0] != Ace {
+		t.Fatalf("unexpected hand %v", res.Hands[0])
+	}
+}
+
+func TestResolveShowdownOddChip(t *testing.T) {
+	// Both players play the board; the odd chip goes to the first seat.
+	res, err := ResolveShowdown(ShowdownConfig{
+		Board: cards("Ah", "Kh", "Qh", "Jh", "Th"),
+		Players: []ShowdownPlayer{
+			{Hole: cards("2c", "3d"), Contributed: 50},
+			{Hole: cards("4c", "5d"), Contributed: 50},
+			{Folded: true, Contributed: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Pots) != 1 || res.Pots[0].Amount != 101 || len(res.Pots[0].Winners) != 2 {
+		t.Fatalf("expected one split pot of 101, got %+v", res.Pots)
+	}
+	if res.Winnings[0] != 51 || res.Winnings[1] != 50 || res.Winnings[2] != 0 {
+		t.Fatalf("unexpected winnings %v", res.Winnings)
+	}
+}
+
+func TestResolveShowdownFoldedChips(t *testing.T) {
+	// A player folds after out-betting the short all-in: the chips above the
+	// all-in go to the live player who matched them.
+	res, err := ResolveShowdown(ShowdownConfig{
+		Board: cards("2c", "7d", "9h", "Ts", "3s"),
+		Players: []ShowdownPlayer{
+			{Hole: cards("Ah", "Ad"), Contributed: 100},
+			{Folded: true, Contributed: 200},
+			{Hole: cards("Kh", "Kd"), Contributed: 300},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Pots) != 2 || res.Pots[0].Amount != 300 || res.Pots[1].Amount != 300 {
+		t.Fatalf("unexpected pots %+v", res.Pots)
+	}
+	if res.Winnings[0] != 300 || res.Winnings[2] != 300 {
+		t.Fatalf("unexpected winnings %v", res.Winnings)
+	}
+}
+
+func TestResolveShowdownOmaha(t *testing.T) {
+	res, err := ResolveShowdown(ShowdownConfig{
+		Game:  GameOmaha4,
+		Board: cards("Kh", "9h", "7h", "5h", "Qh"),
+		Players: []ShowdownPlayer{
+			{Hole: cards("Ah", "Kd", "Qs", "Qc"), Contributed: 100},
+			{Hole: cards("2h", "3h", "4c", "4d"), Contributed: 100},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Winnings[1] != 200 || res.Hands[1].Category != Flush {
+		t.Fatalf("expected the two-heart flush to win, got %v %v", res.Winnings, res.Hands)
+	}
+}
+
+func TestResolveShowdownErrors(t *testing.T) {
+	board := cards("2c", "7d", "9h", "Ts", "3s")
+	cases := []ShowdownConfig{
+		{Board: board, Players: []ShowdownPlayer{{Hole: cards("Ah", "Ad"), Contributed: 10}}},
+		{Board: board[:4], Players: []ShowdownPlayer{{Hole: cards("Ah", "Ad")}, {Hole: cards("Kh", "Kd")}}},
+		{Board: board, Players: []ShowdownPlayer{{Hole: cards("Ah", "Ad")}, {Hole: cards("Ah", "Kd")}}},
+		{Board: board, Players: []ShowdownPlayer{{Hole: cards("Ah")}, {Hole: cards("Kh", "Kd")}}},
+		{Board: board, Players: []ShowdownPlayer{{Hole: cards("Ah", "Ad"), Contributed: -5}, {Hole: cards("Kh", "Kd")}}},
+		{Board: board, Players: []ShowdownPlayer{{Folded: true, Contributed: 10}, {Folded: true}}},
+		{Board: board, Players: []ShowdownPlayer{{Folded: true, Contributed: 10}, {Hole: cards("Kh", "Kd")}}},
+	}
+	for i, cfg := range cases {
+		if _, err := ResolveShowdown(cfg); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
@@ -1,28 +1,105 @@
 package poker
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"runtime"
+	"sync"
 	"time"
 )
 
-// PlayerStyle categorises opponent tendencies used in the simulation.
+// PlayerStyle categorises opponent tendencies used in the simulation. Every
+// style stands for the top share of starting hands given by Percent; see
+// StylePercents and StyleTop.
 type PlayerStyle int
 
 const (
 	StyleBalanced PlayerStyle = iota
 	StyleTight
 	StyleLoose
+	// StyleAny holds any two cards.
+	StyleAny
 )
 
+// SimulationMethod reports how a SimulationResult was obtained.
+type SimulationMethod int
+
+const (
+	MethodMonteCarlo SimulationMethod = iota
+	MethodExact
+	// MethodPrecomputed marks results looked up in the preflop table.
+	MethodPrecomputed
+)
+
+// DefaultExactLimit is the largest number of showdowns enumerated exhaustively
+// when SimulationConfig.ExactLimit is left at zero.
+const DefaultExactLimit = 2000000
+
+const (
+	// sampleBatch is the number of trials played between cancellation,
+	// progress, precision and time budget checks.
+	sampleBatch = 10000
+	// maxAdaptiveTrials caps an adaptive run when Trials is left at zero.
+	maxAdaptiveTrials = 10000000
+)
+
+// Seat describes a single opponent. Known Cards pin the opponent's hand,
+// otherwise a non-empty Range constrains it, otherwise Style applies. Ranges
+// are not available in Omaha.
+type Seat struct {
+	Style PlayerStyle
+	Range Range
+	Cards []Card
+}
+
 // SimulationConfig describes the parameters for a Monte Carlo probability calculation.
 type SimulationConfig struct {
-	Hero      []Card
-	Board     []Card
+	Game  Game
+	Hero  []Card
+	Board []Card
+	// Dead cards are out of play, such as folded hands shown or burn cards
+	// seen; they are never dealt to the board or to opponents.
+	Dead      []Card
 	Opponents int
 	Style     PlayerStyle
 	Trials    int
 	Seed      int64
+	// ExactLimit caps the number of showdowns (unseen runouts × opponent
+	// holdings) that may be enumerated exactly instead of sampled. Zero selects
+	// DefaultExactLimit, a negative value always samples.
+	ExactLimit int
+	// Workers is the number of goroutines sharing the Monte Carlo trials; zero
+	// uses GOMAXPROCS. Each worker draws from its own seed derived from Seed,
+	// so a fixed Seed and Workers pair always reproduces the same result.
+	Workers int
+	// Seats describes every opponent individually. When set, Opponents may be
+	// left at zero (or must equal len(Seats)) and Style is ignored.
+	Seats []Seat
+	// HeroRange replaces Hero for range-vs-range calculations. Hero combos are
+	// dealt together with the opponents, so card removal applies both ways.
+	HeroRange Range
+	// Precision, in percentage points, stops sampling once every outcome's 95%
+	// confidence interval is at most ±Precision. TimeBudget stops sampling
+	// once the duration has elapsed. With either set, Trials is an upper bound
+	// and zero allows up to ten million trials.
+	Precision  float64
+	TimeBudget time.Duration
+	// Progress, when set, is called after every batch of Monte Carlo trials
+	// from the calling goroutine. Exact enumeration does not report progress.
+	Progress func(Progress)
+}
+
+// Progress is an interim report of a running simulation.
+type Progress struct {
+	// Done is the number of trials played so far.
+	Done int
+	// Fraction estimates how much of the run is complete, from 0 to 1.
+	Fraction float64
+	// Result holds the estimate after Done trials.
+	Result SimulationResult
 }
 
 // SimulationResult contains aggregate probabilities.
@@ -30,157 +107,717 @@ type SimulationResult struct {
 	Win  float64
 	Tie  float64
 	Lose float64
+	// WinMargin, TieMargin and LoseMargin are the half-widths of the 95%
+	// confidence intervals in percentage points, zero for exact results.
+	WinMargin  float64
+	TieMargin  float64
+	LoseMargin float64
+	// Equity is the hero's average share of the pot in percent: a win counts
+	// in full and a tie as the hero's split with every player who chopped,
+	// so a three-way chop is worth a third. EquityMargin is its 95%
+	// confidence half-width, zero for exact results.
+	Equity       float64
+	EquityMargin float64
+	Method       SimulationMethod
+	// Samples is the number of trials played or showdowns enumerated.
+	Samples int
+	// Partial is set when the context was cancelled before the run finished;
+	// the result then covers only the trials played so far.
+	Partial bool
+	// Seats reports, in seat order, how each opponent fared against the hero.
+	Seats []SeatResult
+	// Combos breaks the result down per hero combo when the hero holds a
+	// range, in range order. Combos blocked by known cards are omitted.
+	Combos []ComboResult
+	// HeroHands is the percentage of showdowns the hero finished with each
+	// hand category, indexed by HandCategory.
+	HeroHands [StraightFlush + 1]float64
+	// LostTo is the percentage of showdowns lost to each category of the
+	// winning opponent's hand, indexed by HandCategory. It sums to Lose.
+	LostTo [StraightFlush + 1]float64
 }
 
-// SimulateWinProbability estimates hero equity via Monte Carlo sampling.
+// ComboResult describes how a single hero combo fared.
+type ComboResult struct {
+	Combo Combo
+	Win   float64
+	Tie   float64
+	Lose  float64
+	// Equity is the combo's average share of the pot.
+	Equity  float64
+	Samples int
+}
+
+// SeatResult describes one opponent's showdowns against the hero.
+type SeatResult struct {
+	// BeatsHero is the percentage of showdowns this opponent's hand beat the
+	// hero's, regardless of the other opponents.
+	BeatsHero float64
+}
+
+// SimulateWinProbability estimates hero equity. Small spaces of unseen cards
+// are enumerated exactly, everything else falls back to Monte Carlo sampling.
 func SimulateWinProbability(cfg SimulationConfig) (SimulationResult, error) {
-	if len(cfg.Hero) != 2 {
-		return SimulationResult{}, errors.New("hero must have exactly two hole cards")
+	return SimulateWinProbabilityContext(context.Background(), cfg)
+}
+
+// SimulateWinProbabilityContext is SimulateWinProbability with cancellation.
+// When ctx is done mid-run, the trials played so far are returned as a result
+// with Partial set; if none were played, or the run was an exact enumeration,
+// the context error is returned.
+func SimulateWinProbabilityContext(ctx context.Context, cfg SimulationConfig) (SimulationResult, error) {
+	holeCards := cfg.Game.HoleCards()
+	if cfg.HeroRange.IsEmpty() && len(cfg.Hero) != holeCards {
+		return SimulationResult{}, fmt.Errorf("hero must have exactly %d hole cards", holeCards)
+	}
+	if !cfg.HeroRange.IsEmpty() && cfg.Game.IsOmaha() {
+		return SimulationResult{}, errors.New("ranges are not supported in Omaha")
+	}
+	if !cfg.HeroRange.IsEmpty() && len(cfg.Hero) != 0 {
+		return SimulationResult{}, errors.New("hero cannot have both hole cards and a range")
 	}
 	if len(cfg.Board) > 5 {
 		return SimulationResult{}, errors.New("board cannot exceed five cards")
 	}
-	if cfg.Opponents < 1 {
+
+	seats := cfg.Seats
+	if len(seats) == 0 {
+		seats = make([]Seat, cfg.Opponents)
+		for i := range seats {
+			seats[i].Style = cfg.Style
+		}
+	} else if cfg.Opponents != 0 && cfg.Opponents != len(seats) {
+		return SimulationResult{}, errors.New("opponent count does not match the seats")
+	}
+	if len(seats) < 1 {
 		return SimulationResult{}, errors.New("opponent count must be at least one")
 	}
-	if cfg.Opponents > 8 {
+	if len(seats) > 8 {
 		return SimulationResult{}, errors.New("opponent count too large (max 8)")
 	}
 
-	distinct := make(map[Card]struct{}, len(cfg.Hero)+len(cfg.Board))
-	for _, c := range append(append([]Card(nil), cfg.Hero...), cfg.Board...) {
+	known := append(append([]Card(nil), cfg.Hero...), cfg.Board...)
+	for i, seat := range seats {
+		if len(seat.Cards) != 0 && len(seat.Cards) != holeCards {
+			return SimulationResult{}, fmt.Errorf("opponent %d must have exactly %d known cards", i+1, holeCards)
+		}
+		if !seat.Range.IsEmpty() && cfg.Game.IsOmaha() {
+			return SimulationResult{}, errors.New("ranges are not supported in Omaha")
+		}
+		known = append(known, seat.Cards...)
+	}
+	known = append(known, cfg.Dead...)
+	if (len(seats)+1)*holeCards+5+len(cfg.Dead) > cfg.Game.DeckSize() {
+		return SimulationResult{}, errors.New("not enough cards in the deck for every player")
+	}
+
+	distinct := make(map[Card]struct{}, len(known))
+	for _, c := range known {
+		if !cfg.Game.HasCard(c) {
+			return SimulationResult{}, fmt.Errorf("card %s is not in the %s deck", c, cfg.Game)
+		}
 		if _, exists := distinct[c]; exists {
 			return SimulationResult{}, errors.New("duplicate cards provided")
 		}
 		distinct[c] = struct{}{}
 	}
 
-	trials := cfg.Trials
-	if trials <= 0 {
-		trials = 5000
+	tbl, err := newTable(cfg, seats)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	if tbl.canEnumerate(cfg.ExactLimit) {
+		if err := ctx.Err(); err != nil {
+			return SimulationResult{}, err
+		}
+		t, err := tbl.enumerate(ctx)
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		if t.total() == 0 {
+			return SimulationResult{}, errRangeOverlap
+		}
+		return t.result(MethodExact, tbl.heroCombos), nil
 	}
 
 	seed := cfg.Seed
 	if seed == 0 {
 		seed = time.Now().UnixNano()
 	}
-	rng := rand.New(rand.NewSource(seed))
 
-	wins, ties, losses := 0, 0, 0
-	excluded := append([]Card(nil), cfg.Hero...)
-	excluded = append(excluded, cfg.Board...)
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
 
-	for i := 0; i < trials; i++ {
-		deck := BuildDeck(excluded)
-		rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+	t, partial, err := tbl.sampleBatches(ctx, cfg, workers, seed)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	res := t.result(MethodMonteCarlo, tbl.heroCombos)
+	res.Partial = partial
+	return res, nil
+}
+
+// sample splits trials across workers. Every batch uses distinct worker
+// seeds, so a fixed Seed and Workers pair reproduces the whole run.
+func (t *table) sample(trials, workers int, seed int64, batch int) (tally, error) {
+	if workers > trials {
+		workers = trials
+	}
+	return runWorkers(workers, func(worker int) (tally, error) {
+		share := trials / workers
+		if worker < trials%workers {
+			share++
+		}
+		rng := rand.New(rand.NewSource(workerSeed(seed, batch*workers+worker)))
+		return t.monteCarlo(share, rng)
+	})
+}
+
+// sampleBatches plays batches of trials until the trial limit is reached, the
+// target precision is met or the time budget runs out, reporting progress
+// after each batch. A cancelled context stops it early with partial set.
+func (t *table) sampleBatches(ctx context.Context, cfg SimulationConfig, workers int, seed int64) (tally, bool, error) {
+	adaptive := cfg.Precision > 0 || cfg.TimeBudget > 0
+	limit := cfg.Trials
+	switch {
+	case limit > 0:
+	case adaptive:
+		limit = maxAdaptiveTrials
+	default:
+		limit = 5000
+	}
+	start := time.Now()
 
-		board := make([]Card, len(cfg.Board))
-		copy(board, cfg.Board)
-		if len(board) < 5 {
-			needed := 5 - len(board)
-			board = append(board, deck[:needed]...)
-			deck = deck[needed:]
+	var total tally
+	for batch := 0; total.total() < limit; batch++ {
+		if err := ctx.Err(); err != nil {
+			if total.total() == 0 {
+				return tally{}, false, err
+			}
+			return total, true, nil
 		}
 
-		heroCards := append(append([]Card(nil), cfg.Hero...), board...)
-		heroRank, err := EvaluateBestHand(heroCards)
+		part, err := t.sample(min(sampleBatch, limit-total.total()), workers, seed, batch)
 		if err != nil {
-			return SimulationResult{}, err
+			return tally{}, false, err
+		}
+		total.add(part)
+
+		elapsed := time.Since(start)
+		done := cfg.Precision > 0 && total.margin() <= cfg.Precision ||
+			cfg.TimeBudget > 0 && elapsed >= cfg.TimeBudget
+		if cfg.Progress != nil {
+			fraction := 1.0
+			if !done {
+				fraction = total.progress(cfg, limit, elapsed)
+			}
+			cfg.Progress(Progress{
+				Done:     total.total(),
+				Fraction: fraction,
+				Result:   total.result(MethodMonteCarlo, t.heroCombos),
+			})
+		}
+		if done {
+			break
 		}
+	}
+	return total, false, nil
+}
 
-		heroWins := true
-		heroTies := false
+// runWorkers runs fn on the given number of goroutines and merges their
+// tallies. The first error, in worker order, wins.
+func runWorkers(workers int, fn func(worker int) (tally, error)) (tally, error) {
+	tallies := make([]tally, workers)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			tallies[w], errs[w] = fn(w)
+		}(w)
+	}
+	wg.Wait()
 
-		for opp := 0; opp < cfg.Opponents; opp++ {
-			hand := drawOpponentHand(&deck, cfg.Style, rng)
-			if len(hand) != 2 {
-				return SimulationResult{}, errors.New("not enough cards to draw opponent hand")
-			}
+	var total tally
+	for w := 0; w < workers; w++ {
+		if errs[w] != nil {
+			return tally{}, errs[w]
+		}
+		total.add(tallies[w])
+	}
+	return total, nil
+}
+
+// workerSeed derives an independent seed for a worker using the SplitMix64
+// finaliser, so neighbouring workers do not get correlated streams.
+func workerSeed(seed int64, worker int) int64 {
+	z := uint64(seed) + uint64(worker+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// tally accumulates showdown outcomes from the hero's point of view.
+type tally struct {
+	wins, ties, losses int
+	// shares sums the hero's pot shares and squares sums their squares, for
+	// the confidence interval of the equity.
+	shares, squares float64
+	// beaten counts, per seat, the showdowns that seat won against the hero.
+	beaten []int
+	// combos splits the outcomes per hero combo when the hero holds a range.
+	combos []comboTally
+	// hands counts the hero's final categories, lostTo the categories of the
+	// opponent hands that won when the hero lost.
+	hands, lostTo [StraightFlush + 1]int
+}
+
+type comboTally struct {
+	wins, ties, losses int
+	shares             float64
+}
+
+func (c comboTally) total() int {
+	return c.wins + c.ties + c.losses
+}
+
+func newTally(seats, combos int) tally {
+	return tally{beaten: make([]int, seats), combos: make([]comboTally, combos)}
+}
+
+// record adds one showdown won, tied or lost with heroRank; combo indexes the
+// hero combo, or is negative for fixed hole cards.
+func (t *tally) record(v verdict, heroRank HandRank, combo int) {
+	share := v.share()
+	c := comboTally{shares: share}
+	t.shares += share
+	t.squares += share * share
+	switch v.outcome {
+	case outcomeWin:
+		t.wins++
+		c.wins++
+	case outcomeTie:
+		t.ties++
+		c.ties++
+	default:
+		t.losses++
+		c.losses++
+		t.lostTo[v.winner.Category]++
+	}
+	t.hands[heroRank.Category]++
+	for seat := range t.beaten {
+		if v.beaten&(1<<seat) != 0 {
+			t.beaten[seat]++
+		}
+	}
+	if combo >= 0 {
+		t.combos[combo].add(c)
+	}
+}
+
+func (c *comboTally) add(other comboTally) {
+	c.wins += other.wins
+	c.ties += other.ties
+	c.losses += other.losses
+	c.shares += other.shares
+}
+
+func (t *tally) add(other tally) {
+	t.wins += other.wins
+	t.ties += other.ties
+	t.losses += other.losses
+	t.shares += other.shares
+	t.squares += other.squares
+	if t.beaten == nil {
+		t.beaten = make([]int, len(other.beaten))
+		t.combos = make([]comboTally, len(other.combos))
+	}
+	for seat, n := range other.beaten {
+		t.beaten[seat] += n
+	}
+	for i, c := range other.combos {
+		t.combos[i].add(c)
+	}
+	for i := range t.hands {
+		t.hands[i] += other.hands[i]
+		t.lostTo[i] += other.lostTo[i]
+	}
+}
+
+func (t tally) total() int {
+	return t.wins + t.ties + t.losses
+}
+
+// progress estimates the completed share of a run from the trial limit, the
+// time budget and, since the margin shrinks with the square root of the
+// trials, the target precision.
+func (t tally) progress(cfg SimulationConfig, limit int, elapsed time.Duration) float64 {
+	fraction := float64(t.total()) / float64(limit)
+	if cfg.TimeBudget > 0 {
+		fraction = max(fraction, float64(elapsed)/float64(cfg.TimeBudget))
+	}
+	if cfg.Precision > 0 {
+		if m := t.margin(); m > 0 {
+			fraction = max(fraction, (cfg.Precision/m)*(cfg.Precision/m))
+		}
+	}
+	return min(fraction, 1)
+}
+
+// margin returns the widest 95% confidence half-width among the outcomes.
+func (t tally) margin() float64 {
+	total := t.total()
+	return max(marginOfError(t.wins, total), marginOfError(t.ties, total), marginOfError(t.losses, total))
+}
+
+// equityMargin returns the 95% confidence half-width of the equity in
+// percentage points, from the sample variance of the pot shares.
+func (t tally) equityMargin() float64 {
+	total := float64(t.total())
+	if total == 0 {
+		return 0
+	}
+	mean := t.shares / total
+	variance := max(t.squares/total-mean*mean, 0)
+	return 1.96 * math.Sqrt(variance/total) * 100
+}
+
+func (t tally) result(method SimulationMethod, heroCombos []Combo) SimulationResult {
+	total := t.total()
+	res := SimulationResult{
+		Win:     percentage(t.wins, total),
+		Tie:     percentage(t.ties, total),
+		Lose:    percentage(t.losses, total),
+		Equity:  fraction(t.shares, total),
+		Method:  method,
+		Samples: total,
+		Seats:   make([]SeatResult, len(t.beaten)),
+	}
+	if method == MethodMonteCarlo {
+		res.WinMargin = marginOfError(t.wins, total)
+		res.TieMargin = marginOfError(t.ties, total)
+		res.LoseMargin = marginOfError(t.losses, total)
+		res.EquityMargin = t.equityMargin()
+	}
+	for seat, n := range t.beaten {
+		res.Seats[seat].BeatsHero = percentage(n, total)
+	}
+	for i := range t.hands {
+		res.HeroHands[i] = percentage(t.hands[i], total)
+		res.LostTo[i] = percentage(t.lostTo[i], total)
+	}
+	for i, c := range t.combos {
+		n := c.total()
+		if n == 0 {
+			continue
+		}
+		combo := ComboResult{
+			Combo:   heroCombos[i],
+			Win:     percentage(c.wins, n),
+			Tie:     percentage(c.ties, n),
+			Lose:    percentage(c.losses, n),
+			Equity:  fraction(c.shares, n),
+			Samples: n,
+		}
+		res.Combos = append(res.Combos, combo)
+	}
+	return res
+}
+
+type outcome int
+
+const (
+	outcomeWin outcome = iota
+	outcomeTie
+	outcomeLose
+)
+
+// table is the validated form of a SimulationConfig shared by the Monte Carlo
+// sampler and the exact enumerator.
+type table struct {
+	game        Game
+	hero        CardSet
+	board       CardSet
+	boardNeeded int
+	seats       int
+	// deck holds every card not known to be in the hero's hand, on the board,
+	// in an opponent's known hand or dead.
+	deck []Card
+	// heroCombos replaces hero when the hero holds a range.
+	heroCombos []Combo
+	// ranged lists, per opponent with a range, the combos compatible with the
+	// known cards; rangedSeats maps them to seat indices.
+	ranged      [][]Combo
+	rangedSeats []int
+	// fixed holds the hands of opponents with known cards, seated at fixedSeats.
+	fixed      []CardSet
+	fixedSeats []int
+	// styles lists the styles of the remaining opponents, seated at styleSeats.
+	styles     []PlayerStyle
+	styleSeats []int
+}
 
-			oppCards := append(append([]Card(nil), hand...), board...)
-			oppRank, err := EvaluateBestHand(oppCards)
-			if err != nil {
-				return SimulationResult{}, err
+func newTable(cfg SimulationConfig, seats []Seat) (*table, error) {
+	t := &table{
+		game:        cfg.Game,
+		hero:        NewCardSet(cfg.Hero...),
+		board:       NewCardSet(cfg.Board...),
+		boardNeeded: 5 - len(cfg.Board),
+		seats:       len(seats),
+	}
+
+	known := t.hero | t.board | NewCardSet(cfg.Dead...)
+	for _, seat := range seats {
+		known |= NewCardSet(seat.Cards...)
+	}
+	t.deck = cfg.Game.BuildDeck(known.Cards())
+
+	if !cfg.HeroRange.IsEmpty() {
+		t.heroCombos = cfg.HeroRange.compatible(known | t.game.excluded())
+		if len(t.heroCombos) == 0 {
+			return nil, errors.New("hero range conflicts with the known cards")
+		}
+	}
+
+	for i, seat := range seats {
+		switch {
+		case len(seat.Cards) > 0:
+			t.fixed = append(t.fixed, NewCardSet(seat.Cards...))
+			t.fixedSeats = append(t.fixedSeats, i)
+		case !seat.Range.IsEmpty():
+			combos := seat.Range.compatible(known | t.game.excluded())
+			if len(combos) == 0 {
+				return nil, fmt.Errorf("range of opponent %d conflicts with the known cards", i+1)
+			}
+			t.ranged = append(t.ranged, combos)
+			t.rangedSeats = append(t.rangedSeats, i)
+		case seat.Style.Percent() < 100 && !t.game.IsOmaha():
+			// A style short of any two cards is its top range.
+			combos := TopRange(t.game, seat.Style.Percent()).compatible(known)
+			if len(combos) == 0 {
+				return nil, fmt.Errorf("style of opponent %d conflicts with the known cards", i+1)
 			}
+			t.ranged = append(t.ranged, combos)
+			t.rangedSeats = append(t.rangedSeats, i)
+		default:
+			t.styles = append(t.styles, seat.Style)
+			t.styleSeats = append(t.styleSeats, i)
+		}
+	}
+	return t, nil
+}
 
-			switch heroRank.Compare(oppRank) {
-			case -1:
-				heroWins = false
-				heroTies = false
-				goto outcome
-			case 0:
-				heroTies = true
+// maxRangeAttempts bounds the rejection sampling of range-constrained hands.
+const maxRangeAttempts = 1000
+
+// wholeDealAttempts is how many whole deals dealRanged rejects before it
+// settles for redrawing single seats.
+const wholeDealAttempts = 50
+
+// dealRanged picks the hero combo (when the hero holds a range) and one combo
+// per range-constrained opponent so that no card is used twice. Whole deals are
+// rejected on conflict, which keeps every consistent assignment equally likely.
+// Heavily overlapping ranges, such as many tight opponents, rarely produce a
+// clean deal; after wholeDealAttempts a conflicting seat redraws on its own
+// from the combos still available, a close approximation.
+func (t *table) dealRanged(hands []CardSet, rng *rand.Rand) (hero CardSet, heroCombo int, used CardSet, err error) {
+	for attempt := 0; attempt < maxRangeAttempts; attempt++ {
+		hero, heroCombo, used = t.hero, -1, 0
+		if len(t.heroCombos) > 0 {
+			heroCombo = rng.Intn(len(t.heroCombos))
+			hero = t.heroCombos[heroCombo].set()
+			used = hero
+		}
+
+		redraws := 1
+		if attempt >= wholeDealAttempts {
+			redraws = maxRangeAttempts
+		}
+		ok := true
+		for i, combos := range t.ranged {
+			hand := combos[rng.Intn(len(combos))].set()
+			for r := 1; hand&used != 0 && r < redraws; r++ {
+				hand = combos[rng.Intn(len(combos))].set()
 			}
+			if hand&used != 0 {
+				ok = false
+				break
+			}
+			hands[i] = hand
+			used |= hand
+		}
+		if ok {
+			return hero, heroCombo, used, nil
 		}
+	}
+	return 0, 0, 0, errRangeOverlap
+}
+
+var errRangeOverlap = errors.New("ranges overlap too much to deal distinct hands")
+
+// verdict is the hero's standing against the opponents compared so far.
+type verdict struct {
+	outcome outcome
+	// chopped counts the opponents whose hand tied the hero's; it only
+	// matters while no opponent has beaten the hero.
+	chopped int
+	// beaten has bit i set when seat i beat the hero.
+	beaten uint16
+	// winner is the strongest opponent hand that beat the hero.
+	winner HandRank
+}
 
-	outcome:
-		if !heroWins {
-			losses++
-		} else if heroTies {
-			ties++
-		} else {
-			wins++
+// showdown compares the hero against one opponent and folds the comparison
+// into the running verdict.
+func showdown(g Game, heroRank, oppRank HandRank, seat int, v verdict) verdict {
+	switch g.Compare(heroRank, oppRank) {
+	case -1:
+		if v.outcome != outcomeLose || g.Compare(oppRank, v.winner) > 0 {
+			v.winner = oppRank
+		}
+		v.outcome = outcomeLose
+		v.beaten |= 1 << seat
+	case 0:
+		v.chopped++
+		if v.outcome == outcomeWin {
+			v.outcome = outcomeTie
 		}
 	}
+	return v
+}
 
-	return SimulationResult{
-		Win:  percentage(wins, trials),
-		Tie:  percentage(ties, trials),
-		Lose: percentage(losses, trials),
-	}, nil
+// share returns the hero's part of the pot: all of it on a win, an even split
+// with every tied opponent on a chop and nothing on a loss.
+func (v verdict) share() float64 {
+	switch v.outcome {
+	case outcomeWin:
+		return 1
+	case outcomeTie:
+		return 1 / float64(v.chopped+1)
+	default:
+		return 0
+	}
 }
 
-func drawOpponentHand(deck *[]Card, style PlayerStyle, rng *rand.Rand) []Card {
-	cards := *deck
-	if len(cards) < 2 {
-		return nil
-	}
-
-	window := len(cards)
-	if window > 6 {
-		window = 6
-	}
-	bestI, bestJ := 0, 1
-	bestScore := startingHandScore(cards[0], cards[1])
-
-	switch style {
-	case StyleBalanced:
-		// keep defaults
-	case StyleTight:
-		for i := 0; i < window-1; i++ {
-			for j := i + 1; j < window; j++ {
-				score := startingHandScore(cards[i], cards[j])
-				if score > bestScore || (score == bestScore && rng.Intn(2) == 0) {
-					bestScore = score
-					bestI, bestJ = i, j
-				}
+func (t *table) monteCarlo(trials int, rng *rand.Rand) (tally, error) {
+	result := newTally(t.seats, len(t.heroCombos))
+	buf := make([]Card, len(t.deck))
+	rangedHands := make([]CardSet, len(t.ranged))
+
+	for i := 0; i < trials; i++ {
+		hero, heroCombo, used, err := t.dealRanged(rangedHands, rng)
+		if err != nil {
+			return tally{}, err
+		}
+
+		deck := buf[:0]
+		for _, c := range t.deck {
+			if !used.Contains(c) {
+				deck = append(deck, c)
 			}
 		}
-	case StyleLoose:
-		for i := 0; i < window-1; i++ {
-			for j := i + 1; j < window; j++ {
-				score := startingHandScore(cards[i], cards[j])
-				if score < bestScore || (score == bestScore && rng.Intn(2) == 0) {
-					bestScore = score
-					bestI, bestJ = i, j
-				}
+		rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+
+		board := t.board
+		for needed := t.boardNeeded; needed > 0; needed-- {
+			board = board.Add(deck[0])
+			deck = deck[1:]
+		}
+		heroRank := t.game.evaluate(hero, board)
+
+		var v verdict
+		for i, hand := range t.fixed {
+			v = showdown(t.game, heroRank, t.game.evaluate(hand, board), t.fixedSeats[i], v)
+		}
+		for i, hand := range rangedHands {
+			v = showdown(t.game, heroRank, t.game.evaluate(hand, board), t.rangedSeats[i], v)
+		}
+		for i, style := range t.styles {
+			hand, ok := t.drawStyled(&deck, style, rng)
+			if !ok {
+				return tally{}, errors.New("not enough cards to draw opponent hand")
 			}
+			v = showdown(t.game, heroRank, t.game.evaluate(hand, board), t.styleSeats[i], v)
 		}
+		result.record(v, heroRank, heroCombo)
 	}
 
-	var selected [2]Card
-	selected[0] = cards[bestI]
-	selected[1] = cards[bestJ]
+	return result, nil
+}
 
-	if bestI > bestJ {
-		bestI, bestJ = bestJ, bestI
+// drawStyled deals the hole cards of an opponent described only by a style.
+// Outside Omaha only any-two-cards styles are left here; the others are
+// dealt from their top ranges.
+func (t *table) drawStyled(deck *[]Card, style PlayerStyle, rng *rand.Rand) (CardSet, bool) {
+	n := t.game.HoleCards()
+	if t.game.IsOmaha() && style.Percent() < 100 {
+		return drawOmahaHand(deck, n, style.Percent(), rng)
 	}
+	cards := *deck
+	if len(cards) < n {
+		return 0, false
+	}
+	*deck = cards[n:]
+	return NewCardSet(cards[:n]...), true
+}
 
-	cards = append(cards[:bestJ], cards[bestJ+1:]...)
-	cards = append(cards[:bestI], cards[bestI+1:]...)
+// drawOmahaHand deals an n-card Omaha hand from the top percent of hands,
+// ranked by the scores of every pair of cards that could be played together.
+// Hands are drawn from the shuffled deck until one clears the cut off; if
+// none does within maxRangeAttempts the best one seen is kept.
+func drawOmahaHand(deck *[]Card, n int, percent float64, rng *rand.Rand) (CardSet, bool) {
+	cards := *deck
+	if len(cards) < n {
+		return 0, false
+	}
 
-	*deck = cards
-	return selected[:]
+	threshold := omahaThreshold(n, percent)
+	all := uint(1)<<n - 1
+	best, bestScore := make([]Card, n), -1
+	for attempt := 0; attempt < maxRangeAttempts; attempt++ {
+		for j := 0; j < n; j++ {
+			k := j + rng.Intn(len(cards)-j)
+			cards[j], cards[k] = cards[k], cards[j]
+		}
+		score := omahaHandScore(cards, all)
+		if score > bestScore {
+			bestScore = score
+			copy(best, cards[:n])
+		}
+		if score >= threshold {
+			break
+		}
+	}
+
+	hand := NewCardSet(best...)
+	kept := cards[:0]
+	for _, c := range cards {
+		if !hand.Contains(c) {
+			kept = append(kept, c)
+		}
+	}
+	*deck = kept
+	return hand, true
+}
+
+func omahaHandScore(cards []Card, mask uint) int {
+	score := 0
+	for i := 0; mask>>i != 0; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		for j := i + 1; mask>>j != 0; j++ {
+			if mask&(1<<j) != 0 {
+				score += startingHandScore(cards[i], cards[j])
+			}
+		}
+	}
+	return score
 }
 
 func startingHandScore(a, b Card) int {
@@ -210,3 +847,21 @@ func percentage(count, total int) float64 {
 	}
 	return float64(count) * 100 / float64(total)
 }
+
+// fraction is percentage for a fractional count such as summed pot shares.
+func fraction(count float64, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return count * 100 / float64(total)
+}
+
+// marginOfError returns the half-width, in percentage points, of the normal
+// approximation 95% confidence interval around count/total.
+func marginOfError(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	p := float64(count) / float64(total)
+	return 1.96 * math.Sqrt(p*(1-p)/float64(total)) * 100
+}
@@ -1,8 +1,12 @@
 package poker
 
 import (
+	"context"
+	"errors"
 	"math"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSimulateWinProbabilitySum(t *testing.T) {
@@ -89,3 +93,784 @@ func TestSimulateWinProbabilityValidation(t *testing.T) {
 		t.Fatal("expected error for duplicate cards")
 	}
 }
+
+func cards(values ...string) []Card {
+	result := make([]Card, 0, len(values))
+	for _, v := range values {
+		result = append(result, MustParseCard(v))
+	}
+	return result
+}
+
+func TestSimulateWinProbabilityExactRiver(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Board:     cards("Qh", "Jd", "9c", "4s", "2d"),
+		Opponents: 1,
+		Style:     StyleAny,
+		Seed:      1,
+	}
+
+	first, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Method != MethodExact {
+		t.Fatalf("expected exact enumeration on the river")
+	}
+	if first.Samples != 990 {
+		t.Fatalf("expected 990 opponent combos, got %d", first.Samples)
+	}
+
+	cfg.Seed = 2
+	second, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("exact results must not depend on the seed: %+v vs %+v", first, second)
+	}
+}
+
+func TestSimulateWinProbabilityExactMatchesSampling(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("8s", "7s"),
+		Board:     cards("9s", "6d", "2s", "Kc"),
+		Opponents: 1,
+		Style:     StyleAny,
+		Trials:    20000,
+		Seed:      7,
+	}
+
+	exact, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exact.Method != MethodExact || exact.Samples != 46*990 {
+		t.Fatalf("expected exhaustive turn enumeration, got %+v", exact)
+	}
+
+	cfg.ExactLimit = -1
+	sampled, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sampled.Method != MethodMonteCarlo || sampled.Samples != cfg.Trials {
+		t.Fatalf("expected Monte Carlo fallback, got %+v", sampled)
+	}
+	if math.Abs(exact.Win-sampled.Win) > 1.5 {
+		t.Fatalf("sampled win %.2f too far from exact %.2f", sampled.Win, exact.Win)
+	}
+}
+
+func TestSimulateWinProbabilityExactLimit(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:       cards("Ah", "Kh"),
+		Board:      cards("Qh", "Jd", "9c", "4s", "2d"),
+		Opponents:  1,
+		Style:      StyleAny,
+		Trials:     1000,
+		ExactLimit: 500,
+		Seed:       3,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodMonteCarlo {
+		t.Fatalf("expected sampling when outcomes exceed the limit")
+	}
+
+	// A tight opponent is dealt from the top range, which enumerates exactly.
+	cfg.Style = StyleTight
+	cfg.ExactLimit = 0
+	result, err = SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	known := NewCardSet(append(cfg.Hero, cfg.Board...)...)
+	if want := len(TopRange(GameHoldem, 15).compatible(known)); result.Method != MethodExact || result.Samples != want {
+		t.Fatalf("expected %d exact showdowns against the top 15%%, got %+v", want, result)
+	}
+
+	omaha := SimulationConfig{
+		Game:      GameOmaha4,
+		Hero:      cards("Ah", "Kh", "Qd", "Jd"),
+		Board:     cards("Qh", "Jc", "9c", "4s", "2d"),
+		Opponents: 1,
+		Style:     StyleTight,
+		Trials:    1000,
+		Seed:      3,
+	}
+	result, err = SimulateWinProbability(omaha)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodMonteCarlo {
+		t.Fatal("tight Omaha opponents cannot be enumerated")
+	}
+}
+
+func BenchmarkSimulateWinProbability(b *testing.B) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Opponents: 3,
+		Trials:    10000,
+		Seed:      1,
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := SimulateWinProbability(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSimulateWinProbabilityWorkersDeterministic(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Jc", "Td"),
+		Board:     cards("9h", "2s", "Kd"),
+		Opponents: 3,
+		Style:     StyleTight,
+		Trials:    10001,
+		Seed:      2024,
+		Workers:   4,
+	}
+
+	first, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same seed and workers must reproduce the result: %+v vs %+v", first, second)
+	}
+	if first.Samples != cfg.Trials {
+		t.Fatalf("expected %d trials across workers, got %d", cfg.Trials, first.Samples)
+	}
+
+	cfg.Workers = 1
+	single, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(single.Win-first.Win) > 2 {
+		t.Fatalf("worker count should not bias the estimate: %.2f vs %.2f", single.Win, first.Win)
+	}
+}
+
+func TestSimulateWinProbabilityAgainstRange(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kd"),
+		Board:     cards("Qh", "Jd", "9c", "4s", "2d"),
+		Opponents: 1,
+		Seats:     []Seat{{Range: MustParseRange("QQ")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodExact || result.Samples != 3 {
+		t.Fatalf("expected the three remaining QQ combos to be enumerated, got %+v", result)
+	}
+	if result.Lose != 100 {
+		t.Fatalf("expected hero to lose to a set every time, got %.2f%%", result.Lose)
+	}
+}
+
+func TestSimulateWinProbabilityRangeCardRemoval(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("2c", "3d"),
+		Board:     cards("Kh", "8s", "7d", "4c", "Jd"),
+		Opponents: 2,
+		Seats:     []Seat{{Range: MustParseRange("AA")}, {Range: MustParseRange("AA")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != 6 {
+		t.Fatalf("expected 6 ways to split the aces between two opponents, got %d", result.Samples)
+	}
+
+	cfg.ExactLimit = -1
+	cfg.Trials = 2000
+	cfg.Seed = 5
+	if _, err := SimulateWinProbability(cfg); err != nil {
+		t.Fatalf("unexpected sampling error: %v", err)
+	}
+
+	cfg.Seats = []Seat{{Range: MustParseRange("2c3d")}}
+	cfg.Opponents = 1
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for a range blocked by the hero's cards")
+	}
+
+	// Three aces are left, so two opponents on AA cannot both be dealt.
+	cfg.Hero = cards("Ah", "Kd")
+	cfg.Seats = []Seat{{Range: MustParseRange("AA")}, {Range: MustParseRange("AA")}}
+	cfg.Opponents = 2
+	for _, limit := range []int{0, -1} {
+		cfg.ExactLimit = limit
+		if _, err := SimulateWinProbability(cfg); err == nil {
+			t.Fatalf("expected error for overlapping ranges with exact limit %d", limit)
+		}
+	}
+}
+
+func TestSimulateWinProbabilityRangeSampling(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "As"),
+		Opponents: 1,
+		Seats:     []Seat{{Range: MustParseRange("KK")}},
+		Trials:    20000,
+		Seed:      9,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodMonteCarlo {
+		t.Fatalf("expected preflop range spot to be sampled")
+	}
+	if result.Win < 79 || result.Win > 84 {
+		t.Fatalf("expected AA to win about 81%% against KK, got %.2f%%", result.Win)
+	}
+}
+
+func TestSimulateWinProbabilitySeats(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:  cards("Kd", "Kc"),
+		Board: cards("Qh", "7d", "2c", "9s", "3h"),
+		Seats: []Seat{
+			{Cards: cards("Ah", "As")},
+			{Style: StyleAny},
+		},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodExact || result.Samples != 903 {
+		t.Fatalf("expected the random seat to be enumerated, got %+v", result)
+	}
+	if len(result.Seats) != 2 {
+		t.Fatalf("expected per-seat results, got %d", len(result.Seats))
+	}
+	if result.Seats[0].BeatsHero != 100 {
+		t.Fatalf("pocket aces must always beat kings here, got %.2f%%", result.Seats[0].BeatsHero)
+	}
+	if result.Seats[1].BeatsHero <= 0 || result.Seats[1].BeatsHero >= 20 {
+		t.Fatalf("unexpected random seat result %.2f%%", result.Seats[1].BeatsHero)
+	}
+	if result.Lose != 100 {
+		t.Fatalf("expected hero to lose every showdown, got %.2f%%", result.Lose)
+	}
+}
+
+func TestSimulateWinProbabilityMixedStyles(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:   cards("9c", "9d"),
+		Seats:  []Seat{{Style: StyleTight}, {Style: StyleLoose}, {Style: StyleLoose}},
+		Trials: 20000,
+		Seed:   17,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Seats[0].BeatsHero <= result.Seats[1].BeatsHero {
+		t.Fatalf("tight seat should beat the hero more often than a loose one: %+v", result.Seats)
+	}
+}
+
+func TestSimulateWinProbabilitySeatValidation(t *testing.T) {
+	base := SimulationConfig{Hero: cards("Ah", "Kh")}
+
+	cfg := base
+	cfg.Seats = []Seat{{Cards: cards("Ah", "2c")}}
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for seat cards duplicating the hero")
+	}
+
+	cfg = base
+	cfg.Seats = []Seat{{Cards: cards("2c")}}
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for a single known card")
+	}
+
+	cfg = base
+	cfg.Opponents = 3
+	cfg.Seats = []Seat{{}, {}}
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for mismatched opponent count")
+	}
+}
+
+func TestSimulateWinProbabilityDeadCards(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:  cards("Ah", "Kh"),
+		Board: cards("2c", "7d", "9h", "Ts"),
+		Seats: []Seat{{Cards: cards("Qs", "Qd")}},
+	}
+	live, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if live.Samples != 44 || math.Abs(live.Win-percentage(6, 44)) > 1e-9 {
+		t.Fatalf("expected six outs among 44 rivers, got %+v", live)
+	}
+
+	// A folded ace and a burnt jack leave five outs among 42 rivers.
+	cfg.Dead = cards("Ac", "Jd")
+	dead, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dead.Samples != 42 || math.Abs(dead.Win-percentage(5, 42)) > 1e-9 {
+		t.Fatalf("expected five outs among 42 rivers, got %+v", dead)
+	}
+
+	cfg.Seats = []Seat{{Range: MustParseRange("AA, QQ")}}
+	ranged, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Only AsAd is left of the aces: seven combos over 42 rivers.
+	if ranged.Samples != 7*42 {
+		t.Fatalf("expected dead cards to block range combos, got %d samples", ranged.Samples)
+	}
+
+	for _, dead := range [][]Card{cards("Ah"), cards("7d"), cards("Qs"), cards("5c", "5c")} {
+		cfg.Seats = []Seat{{Cards: cards("Qs", "Qd")}}
+		cfg.Dead = dead
+		if _, err := SimulateWinProbability(cfg); err == nil {
+			t.Fatalf("expected error for dead cards %v", dead)
+		}
+	}
+}
+
+func TestSimulateWinProbabilityMultiwayChop(t *testing.T) {
+	board := cards("2c", "5d", "9h", "Js", "Kc")
+	cases := []struct {
+		name   string
+		seats  []Seat
+		tie    float64
+		equity float64
+	}{
+		{"three-way chop", []Seat{{Cards: cards("Ad", "Qc")}, {Cards: cards("As", "Qs")}}, 100, 100.0 / 3},
+		{"chop with one of two", []Seat{{Cards: cards("Ad", "Qc")}, {Cards: cards("7c", "8d")}}, 100, 50},
+		{"chop then beaten", []Seat{{Cards: cards("Ad", "Qc")}, {Cards: cards("Kd", "Ks")}}, 0, 0},
+		{"beaten then chop", []Seat{{Cards: cards("Kd", "Ks")}, {Cards: cards("Ad", "Qc")}}, 0, 0},
+	}
+	for _, tc := range cases {
+		result, err := SimulateWinProbability(SimulationConfig{Hero: cards("Ac", "Qd"), Board: board, Seats: tc.seats})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if result.Tie != tc.tie || math.Abs(result.Equity-tc.equity) > 1e-9 {
+			t.Fatalf("%s: expected tie %.2f and equity %.2f, got %+v", tc.name, tc.tie, tc.equity, result)
+		}
+		if tc.tie == 0 && result.Lose != 100 {
+			t.Fatalf("%s: expected a loss, got %+v", tc.name, result)
+		}
+	}
+}
+
+func TestSimulateWinProbabilityEquity(t *testing.T) {
+	headsUp, err := SimulateWinProbability(SimulationConfig{Hero: cards("8c", "8d"), Opponents: 1, Trials: 20000, Seed: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(headsUp.Equity-(headsUp.Win+headsUp.Tie/2)) > 1e-9 {
+		t.Fatalf("heads-up equity must split ties in half, got %+v", headsUp)
+	}
+	if headsUp.EquityMargin <= 0 || headsUp.EquityMargin > headsUp.WinMargin+headsUp.TieMargin {
+		t.Fatalf("unexpected equity margin %.3f", headsUp.EquityMargin)
+	}
+
+	// With a flush on board most showdowns are multiway chops worth a third.
+	multiway, err := SimulateWinProbability(SimulationConfig{
+		Hero:      cards("2c", "3d"),
+		Board:     cards("Ah", "Kh", "Qh", "Jh", "Th"),
+		Opponents: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if multiway.Tie != 100 || math.Abs(multiway.Equity-100.0/3) > 1e-9 {
+		t.Fatalf("expected a three-way chop, got %+v", multiway)
+	}
+}
+
+func TestSimulateRangeVsRangeExact(t *testing.T) {
+	cfg := SimulationConfig{
+		HeroRange: MustParseRange("KK, 22"),
+		Board:     cards("Kh", "7d", "2c", "3s", "9h"),
+		Seats:     []Seat{{Range: MustParseRange("AA")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodExact || result.Samples != 36 {
+		t.Fatalf("expected 6 hero combos × 6 aces to be enumerated, got %+v", result)
+	}
+	if result.Win != 100 {
+		t.Fatalf("sets must always beat aces here, got %.2f%%", result.Win)
+	}
+	if len(result.Combos) != 6 {
+		t.Fatalf("expected 6 hero combos after card removal, got %d", len(result.Combos))
+	}
+	for _, c := range result.Combos {
+		if c.Samples != 6 || c.Equity != 100 {
+			t.Fatalf("unexpected combo breakdown %+v", c)
+		}
+	}
+}
+
+func TestSimulateRangeVsRangeCardRemoval(t *testing.T) {
+	cfg := SimulationConfig{
+		HeroRange: MustParseRange("AA"),
+		Board:     cards("Kh", "7d", "2c", "3s", "9h"),
+		Seats:     []Seat{{Range: MustParseRange("AA")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != 6 || result.Tie != 100 {
+		t.Fatalf("expected every hero combo to chop with the single remaining one, got %+v", result)
+	}
+	for _, c := range result.Combos {
+		if c.Equity != 50 {
+			t.Fatalf("expected a chop to count as half the pot, got %+v", c)
+		}
+	}
+}
+
+func TestSimulateRangeVsRangeSampling(t *testing.T) {
+	cfg := SimulationConfig{
+		HeroRange: MustParseRange("AA"),
+		Seats:     []Seat{{Range: MustParseRange("KK")}},
+		Trials:    30000,
+		Seed:      21,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Win < 79 || result.Win > 84 {
+		t.Fatalf("expected AA to win about 81%% against KK, got %.2f%%", result.Win)
+	}
+	if len(result.Combos) != 6 {
+		t.Fatalf("expected a breakdown for all six aces, got %d", len(result.Combos))
+	}
+
+	cfg.Hero = cards("Ah", "As")
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error when both hero cards and a hero range are given")
+	}
+}
+
+func TestSimulateWinProbabilityOmahaRules(t *testing.T) {
+	cfg := SimulationConfig{
+		Game:  GameOmaha4,
+		Hero:  cards("Ah", "Kc", "Qd", "Js"),
+		Board: cards("2h", "5h", "8h", "Th", "3c"),
+		Seats: []Seat{{Cards: cards("9h", "7h", "2c", "2d")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != 1 || result.Lose != 100 {
+		t.Fatalf("a single heart in hand makes no flush in Omaha, got %+v", result)
+	}
+}
+
+func TestSimulateWinProbabilityOmahaExactRiver(t *testing.T) {
+	cfg := SimulationConfig{
+		Game:      GameOmaha4,
+		Hero:      cards("Ah", "Ad", "Ks", "Kc"),
+		Board:     cards("2h", "7d", "Tc", "Jh", "4s"),
+		Opponents: 1,
+		Style:     StyleAny,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodExact || result.Samples != 123410 {
+		t.Fatalf("expected every four-card opponent hand to be enumerated, got %+v", result)
+	}
+	if sum := result.Win + result.Tie + result.Lose; math.Abs(sum-100) > 0.01 {
+		t.Fatalf("probabilities must sum to 100, got %.4f", sum)
+	}
+}
+
+func TestSimulateWinProbabilityOmahaSampling(t *testing.T) {
+	cfg := SimulationConfig{
+		Game:      GameOmaha5,
+		Hero:      cards("Ah", "Ad", "Kh", "Kd", "Qs"),
+		Opponents: 8,
+		Style:     StyleTight,
+		Trials:    2000,
+		Seed:      4,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != cfg.Trials || len(result.Seats) != 8 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	cfg.Hero = cards("Ah", "Ad")
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for two hole cards in PLO5")
+	}
+
+	cfg.Game = GameOmaha4
+	cfg.Hero = cards("Ah", "Ad", "Kh", "Kd")
+	cfg.Seats = []Seat{{Range: MustParseRange("QQ+")}}
+	cfg.Opponents = 0
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for ranges in Omaha")
+	}
+}
+
+func TestSimulateWinProbabilityShortDeck(t *testing.T) {
+	cfg := SimulationConfig{
+		Game:      GameShortDeck,
+		Hero:      cards("Ah", "Ad"),
+		Board:     cards("Kc", "Qd", "8h", "7s", "6c"),
+		Opponents: 1,
+		Style:     StyleAny,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Method != MethodExact || result.Samples != 406 {
+		t.Fatalf("expected every hand from the 29 unseen cards, got %+v", result)
+	}
+
+	// Low pairs are stripped from the range together with the low cards.
+	cfg.Opponents = 0
+	cfg.Seats = []Seat{{Range: MustParseRange("22-77")}}
+	result, err = SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != 6 {
+		t.Fatalf("expected only the remaining sixes and sevens, got %d samples", result.Samples)
+	}
+
+	cfg.Hero = cards("Ah", "5d")
+	if _, err := SimulateWinProbability(cfg); err == nil {
+		t.Fatal("expected error for a card outside the short deck")
+	}
+}
+
+func TestSimulateWinProbabilityHandCategories(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:  cards("Ah", "Kd"),
+		Board: cards("Qh", "7c", "2d"),
+		Seats: []Seat{{Cards: cards("Qs", "Qc")}},
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var hands, lost float64
+	for category := HighCard; category <= StraightFlush; category++ {
+		hands += result.HeroHands[category]
+		lost += result.LostTo[category]
+	}
+	if math.Abs(hands-100) > 0.01 || math.Abs(lost-result.Lose) > 0.01 {
+		t.Fatalf("expected categories to sum to 100%% and %.2f%%, got %.2f%% and %.2f%%", result.Lose, hands, lost)
+	}
+	// Flopped trips never lose as less than trips.
+	if result.LostTo[HighCard] != 0 || result.LostTo[OnePair] != 0 || result.LostTo[TwoPair] != 0 {
+		t.Fatalf("unexpected losing categories %v", result.LostTo)
+	}
+	if result.LostTo[ThreeOfAKind] == 0 || result.HeroHands[OnePair] == 0 {
+		t.Fatalf("expected losses to trips and hero pairs, got %v and %v", result.LostTo, result.HeroHands)
+	}
+}
+
+func TestSimulateWinProbabilityMargins(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Opponents: 2,
+		Trials:    4000,
+		Seed:      5,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := result.Win / 100
+	if want := 196 * math.Sqrt(p*(1-p)/4000); math.Abs(result.WinMargin-want) > 1e-9 {
+		t.Fatalf("expected win margin %.4f, got %.4f", want, result.WinMargin)
+	}
+	if result.TieMargin <= 0 || result.LoseMargin <= 0 {
+		t.Fatalf("expected positive margins, got %+v", result)
+	}
+
+	exact, err := SimulateWinProbability(SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Board:     cards("Qh", "Jh", "2c", "3d", "4s"),
+		Opponents: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exact.WinMargin != 0 || exact.TieMargin != 0 || exact.LoseMargin != 0 {
+		t.Fatalf("expected no margin for exact results, got %+v", exact)
+	}
+}
+
+func TestSimulateWinProbabilityPrecision(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Opponents: 2,
+		Seed:      6,
+		Workers:   2,
+		Precision: 0.8,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.WinMargin > 0.8 || result.TieMargin > 0.8 || result.LoseMargin > 0.8 {
+		t.Fatalf("expected every margin within the target, got %+v", result)
+	}
+	if result.Samples%sampleBatch != 0 || result.Samples > 3*sampleBatch {
+		t.Fatalf("expected to stop after a few batches, got %d samples", result.Samples)
+	}
+
+	again, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, again) {
+		t.Fatal("expected adaptive runs to be reproducible")
+	}
+
+	cfg.Precision = 0.01
+	cfg.Trials = 15000
+	capped, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if capped.Samples != 15000 {
+		t.Fatalf("expected trials to cap the run, got %d", capped.Samples)
+	}
+}
+
+func TestSimulateWinProbabilityTimeBudget(t *testing.T) {
+	cfg := SimulationConfig{
+		Hero:       cards("Ah", "Kh"),
+		Opponents:  2,
+		Seed:       7,
+		TimeBudget: time.Nanosecond,
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Samples != sampleBatch {
+		t.Fatalf("expected a single batch once the budget is spent, got %d", result.Samples)
+	}
+}
+
+func TestSimulateWinProbabilityProgress(t *testing.T) {
+	var reports []Progress
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Opponents: 1,
+		Trials:    25000,
+		Seed:      8,
+		Progress:  func(p Progress) { reports = append(reports, p) },
+	}
+
+	result, err := SimulateWinProbability(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Partial || len(reports) != 3 {
+		t.Fatalf("expected three progress reports for a full run, got %d", len(reports))
+	}
+	for i, want := range []int{10000, 20000, 25000} {
+		if reports[i].Done != want || reports[i].Result.Samples != want {
+			t.Fatalf("report %d: expected %d trials, got %+v", i, want, reports[i])
+		}
+	}
+	if reports[0].Fraction != 0.4 || reports[2].Fraction != 1 {
+		t.Fatalf("unexpected fractions %.2f and %.2f", reports[0].Fraction, reports[2].Fraction)
+	}
+	if !reflect.DeepEqual(reports[2].Result, result) {
+		t.Fatal("expected the last report to match the result")
+	}
+}
+
+func TestSimulateWinProbabilityContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Opponents: 1,
+		Trials:    100000,
+		Seed:      9,
+		Progress:  func(Progress) { cancel() },
+	}
+
+	result, err := SimulateWinProbabilityContext(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Partial || result.Samples != sampleBatch {
+		t.Fatalf("expected a partial result after one batch, got %+v", result)
+	}
+
+	if _, err := SimulateWinProbabilityContext(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error before any trial, got %v", err)
+	}
+}
+
+func TestSimulateWinProbabilityContextDeadlineExact(t *testing.T) {
+	// A heads-up flop enumerates about a million showdowns, far more than a
+	// millisecond allows.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	cfg := SimulationConfig{
+		Hero:      cards("Ah", "Kh"),
+		Board:     cards("Qh", "7d", "2c"),
+		Opponents: 1,
+		Style:     StyleAny,
+	}
+	if _, err := SimulateWinProbabilityContext(ctx, cfg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the enumeration, got %v", err)
+	}
+}
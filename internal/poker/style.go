@@ -0,0 +1,121 @@
+package poker
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StylePercents maps the named styles to the share of starting hands, in
+// percent, an opponent of that style plays. Changing the values invalidates
+// the matching preflop table entries, which are then simulated afresh.
+var StylePercents = map[PlayerStyle]float64{
+	StyleBalanced: 25,
+	StyleTight:    15,
+	StyleLoose:    50,
+	StyleAny:      100,
+}
+
+// styleTopBase offsets custom percentage styles from the named ones; the
+// percentage is stored in tenths above it.
+const styleTopBase PlayerStyle = 1000
+
+// StyleTop returns a custom style playing the top percent of starting hands,
+// rounded to a tenth of a percent. Percent must be in (0, 100].
+func StyleTop(percent float64) (PlayerStyle, error) {
+	tenths := math.Round(percent * 10)
+	if tenths < 1 || tenths > 1000 {
+		return 0, fmt.Errorf("style percentage must be between 0.1 and 100, got %g", percent)
+	}
+	return styleTopBase + PlayerStyle(tenths), nil
+}
+
+// Percent returns the share of starting hands the style plays, in percent.
+func (s PlayerStyle) Percent() float64 {
+	if s > styleTopBase {
+		return float64(s-styleTopBase) / 10
+	}
+	if p, ok := StylePercents[s]; ok {
+		return p
+	}
+	return 100
+}
+
+// IsCustom reports whether the style was created by StyleTop.
+func (s PlayerStyle) IsCustom() bool {
+	return s > styleTopBase
+}
+
+// preflopRanking orders the 169 starting hands from strongest to weakest by
+// heads-up all-in equity against a random hand, as in the preflop table.
+var preflopRanking = strings.Fields(`
+	AA KK QQ JJ TT 99 88 AKs AQs 77 AKo AJs ATs AQo AJo KQs ATo 66 A9s KJs KTs A8s KQo A7s A9o KJo 55
+	QJs K9s A6s A5s QTs KTo A8o A4s A7o A3s K8s QJo A6o A5o Q9s A2s JTs QTo K9o K7s 44 A4o K6s Q8s K5s
+	K8o J9s A3o JTo Q9o K7o A2o K4s Q7s K6o K3s J8s T9s Q6s 33 Q8o K5o Q5s J9o K2s J7s T8s K4o Q7o J8o
+	Q4s T9o K3o Q3s Q6o K2o 98s T7s 22 J6s Q2s J5s Q5o T8o J7o 97s Q4o T6s J4s T7o J3s Q3o 98o 87s Q2o
+	96s J6o T5s J2s J5o T4s 86s 97o J4o T6o T3s 95s 76s J3o 87o T2s 85s 96o J2o T5o 94s 75s 86o T4o 93s
+	65s T3o 95o 84s 92s 76o 74s T2o 85o 54s 64s 83s 94o 75o 82s 73s 93o 65o 63s 84o 53s 92o 43s 74o 54o
+	72s 52s 62s 64o 83o 42s 82o 73o 32s 53o 63o 43o 72o 52o 62o 42o 32o`)
+
+// TopRange returns the strongest starting hands of the game's deck that make
+// up at least percent of all its two-card combos, by the preflop ranking.
+// Short Deck skips the hands with stripped ranks.
+func TopRange(g Game, percent float64) Range {
+	total := binomial(g.DeckSize(), 2)
+	target := int(math.Ceil(percent / 100 * float64(total)))
+
+	var r Range
+	for _, hand := range preflopRanking {
+		if len(r.combos) >= max(target, 1) {
+			break
+		}
+		class, _ := parseHandClass(hand)
+		if class.low < g.lowestRank() {
+			continue
+		}
+		r.combos = append(r.combos, class.combos()...)
+		r.tokens = append(r.tokens, hand)
+	}
+	return r
+}
+
+// omahaThresholdSamples is the number of random hands scored to find the cut
+// off of a percentage style in Omaha.
+const omahaThresholdSamples = 50000
+
+var omahaThresholds sync.Map // omahaThresholdKey -> int
+
+type omahaThresholdKey struct {
+	holeCards int
+	percent   float64
+}
+
+// omahaThreshold returns the smallest pair score of the top percent of Omaha
+// hands, estimated once per hand size and percentage from a fixed sample of
+// random hands so that simulations stay reproducible.
+func omahaThreshold(n int, percent float64) int {
+	key := omahaThresholdKey{holeCards: n, percent: percent}
+	if v, ok := omahaThresholds.Load(key); ok {
+		return v.(int)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	deck := BuildDeck(nil)
+	scores := make([]int, omahaThresholdSamples)
+	all := uint(1)<<n - 1
+	for i := range scores {
+		for j := 0; j < n; j++ {
+			k := j + rng.Intn(len(deck)-j)
+			deck[j], deck[k] = deck[k], deck[j]
+		}
+		scores[i] = omahaHandScore(deck, all)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+	cut := min(int(percent/100*float64(len(scores))), len(scores)-1)
+
+	v, _ := omahaThresholds.LoadOrStore(key, scores[cut])
+	return v.(int)
+}
@@ -0,0 +1,95 @@
+package poker
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStyleTop(t *testing.T) {
+	style, err := StyleTop(22)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !style.IsCustom() || style.Percent() != 22 {
+		t.Fatalf("expected a custom 22%% style, got %v (%.1f%%)", style, style.Percent())
+	}
+	if other, _ := StyleTop(22.04); other != style {
+		t.Fatal("expected percentages to round to a tenth")
+	}
+	if StyleTight.IsCustom() || StyleTight.Percent() != 15 || StyleLoose.Percent() != 50 || StyleBalanced.Percent() != 25 || StyleAny.Percent() != 100 {
+		t.Fatal("unexpected named style percentages")
+	}
+
+	for _, percent := range []float64{0, 0.01, -5, 100.1} {
+		if _, err := StyleTop(percent); err == nil {
+			t.Fatalf("expected error for %g%%", percent)
+		}
+	}
+}
+
+func TestPreflopRanking(t *testing.T) {
+	if len(preflopRanking) != 169 {
+		t.Fatalf("expected 169 hands, got %d", len(preflopRanking))
+	}
+	seen := make(map[string]bool)
+	for _, hand := range preflopRanking {
+		seen[hand] = true
+	}
+	for _, hand := range StartingHands() {
+		if !seen[hand] {
+			t.Fatalf("ranking misses %s", hand)
+		}
+	}
+}
+
+func TestTopRange(t *testing.T) {
+	tight := TopRange(GameHoldem, 15)
+	if tight.Len() < 199 || tight.Len() > 199+12 {
+		t.Fatalf("expected about 15%% of 1326 combos, got %d", tight.Len())
+	}
+	if combos := tight.Combos(); combos[0].Class() != "AA" {
+		t.Fatalf("expected the range to start with aces, got %s", combos[0])
+	}
+	if tight.String()[:10] != "AA, KK, QQ" {
+		t.Fatalf("unexpected range %s", tight)
+	}
+	if n := TopRange(GameHoldem, 100).Len(); n != 1326 {
+		t.Fatalf("expected every combo at 100%%, got %d", n)
+	}
+	if n := TopRange(GameHoldem, 0.1).Len(); n != 6 {
+		t.Fatalf("expected at least one hand, got %d combos", n)
+	}
+
+	short := TopRange(GameShortDeck, 100)
+	if short.Len() != 630 {
+		t.Fatalf("expected the 630 short deck combos, got %d", short.Len())
+	}
+	for _, c := range short.Combos() {
+		if !GameShortDeck.HasCard(c[0]) || !GameShortDeck.HasCard(c[1]) {
+			t.Fatalf("unexpected short deck combo %s", c)
+		}
+	}
+}
+
+func TestDrawOmahaHand(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	threshold := omahaThreshold(4, 15)
+	for i := 0; i < 200; i++ {
+		deck := BuildDeck(nil)
+		hand, ok := drawOmahaHand(&deck, 4, 15, rng)
+		if !ok || hand.Len() != 4 || len(deck) != 48 {
+			t.Fatalf("unexpected draw %v with %d cards left", hand, len(deck))
+		}
+		if score := omahaHandScore(hand.Cards(), 1<<4-1); score < threshold {
+			t.Fatalf("expected a hand above the cut off %d, got %d", threshold, score)
+		}
+		for _, c := range deck {
+			if hand.Contains(c) {
+				t.Fatalf("card %s left in the deck", c)
+			}
+		}
+	}
+	if omahaThreshold(4, 50) >= threshold {
+		t.Fatal("expected a looser cut off for a wider range")
+	}
+}